@@ -0,0 +1,344 @@
+// Package siocvet defines an Analyzer that reports common misuses of sioc.
+package siocvet
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const doc = `report common misuses of sioc
+
+The siocvet analyzer reports:
+  - Inject of struct values and v0 Register of non-pointer values;
+  - Get[T] for types that are not injected by the package, when the package
+    registers services itself;
+  - Init methods of injected services with parameters that are not injected;
+  - use of the v0 global API in packages that already use sioc/v1, except for
+    UseContainer, Snapshot and Restore, which bridge the two.
+
+Services registered with InjectPooled, and those returned by the
+ProvideService methods of registered providers declared in the package,
+count as injected.`
+
+const (
+	rootPath = "github.com/sergiodii/sioc"
+	v0Path   = "github.com/sergiodii/sioc/v0"
+	v1Path   = "github.com/sergiodii/sioc/v1"
+)
+
+// Analyzer reports common misuses of sioc.
+var Analyzer = &analysis.Analyzer{
+	Name:     "siocvet",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// registration is a service type registered by a call in the package.
+type registration struct {
+	serviceType types.Type
+	call        *ast.CallExpr
+	v0          bool
+}
+
+// lookup is a Get[T] call in the package.
+type lookup struct {
+	targetType types.Type
+	call       *ast.CallExpr
+	v0         bool
+}
+
+// bridgeFunctions of the v0 global API are meant to be used alongside sioc/v1.
+var bridgeFunctions = map[string]bool{
+	"UseContainer": true,
+	"Snapshot":     true,
+	"Restore":      true,
+}
+
+// providedTypes are registered by siochttp and siocgrpc in every request scope.
+var providedTypes = map[string]bool{
+	"context.Context":                    true,
+	"*net/http.Request":                  true,
+	"google.golang.org/grpc/metadata.MD": true,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	usesV1 := false
+	for _, imported := range pass.Pkg.Imports() {
+		if imported.Path() == v1Path {
+			usesV1 = true
+		}
+	}
+
+	var registrations []registration
+	var lookups []lookup
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		function, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || function.Pkg() == nil {
+			return
+		}
+
+		switch function.Pkg().Path() {
+		case v1Path:
+			switch function.Name() {
+			case "Inject":
+				registrations = append(registrations, checkInjectedValue(pass, call, call.Args[0]))
+			case "InjectIf":
+				registrations = append(registrations, checkInjectedValue(pass, call, call.Args[1]))
+			case "InjectAs", "InjectPooled":
+				registrations = append(registrations, registration{serviceType: typeArgument(pass, call), call: call})
+			case "BindConfig":
+				registration := registration{call: call}
+				if configType := typeArgument(pass, call); configType != nil {
+					registration.serviceType = types.NewPointer(configType)
+				}
+				registrations = append(registrations, registration)
+			case "Get":
+				lookups = append(lookups, lookup{targetType: typeArgument(pass, call), call: call})
+			}
+		case v0Path, rootPath:
+			if usesV1 && function.Type().(*types.Signature).Recv() == nil && !bridgeFunctions[function.Name()] {
+				pass.Reportf(call.Pos(), "v0 global API %s.%s used in a package that uses sioc/v1; use a v1 container instead", function.Pkg().Name(), function.Name())
+			}
+			switch function.Name() {
+			case "Register":
+				argumentType := pass.TypesInfo.TypeOf(call.Args[0])
+				if !isPointerOrInterface(argumentType) {
+					pass.Reportf(call.Args[0].Pos(), "Register of non-pointer value of type %s fails at runtime; register a pointer", typeString(pass, argumentType))
+				}
+				registrations = append(registrations, registration{serviceType: argumentType, call: call, v0: true})
+			case "Get":
+				lookups = append(lookups, lookup{targetType: typeArgument(pass, call), call: call, v0: true})
+			}
+		}
+	})
+
+	// Lookups and Init parameters are only checked in packages that set the container up.
+	if len(registrations) == 0 {
+		return nil, nil
+	}
+
+	// Providers can return other providers, so the slice grows while it is walked.
+	methods := methodDeclarations(pass)
+	for index := 0; index < len(registrations); index++ {
+		registrations = append(registrations, providedRegistrations(pass, methods, registrations[index])...)
+	}
+
+	for _, l := range lookups {
+		if l.targetType != nil && !isSatisfied(registrations, l.targetType, l.v0) {
+			pass.Reportf(l.call.Pos(), "Get[%s]: no service of this type is injected in package %s", typeString(pass, l.targetType), pass.Pkg.Name())
+		}
+	}
+
+	for _, r := range registrations {
+		checkInitParameters(pass, registrations, r)
+	}
+	return nil, nil
+}
+
+// methodDeclarations maps the methods declared in the package to their declarations.
+func methodDeclarations(pass *analysis.Pass) map[*types.Func]*ast.FuncDecl {
+	methods := map[*types.Func]*ast.FuncDecl{}
+	for _, file := range pass.Files {
+		for _, declaration := range file.Decls {
+			if function, ok := declaration.(*ast.FuncDecl); ok && function.Recv != nil && function.Body != nil {
+				if method, ok := pass.TypesInfo.Defs[function.Name].(*types.Func); ok {
+					methods[method] = function
+				}
+			}
+		}
+	}
+	return methods
+}
+
+// providedRegistrations returns the services a registered ServiceProvider provides, read from
+// the return statements of its ProvideService method. Providers declared in other packages
+// could provide anything, so they yield a registration of unknown type.
+func providedRegistrations(pass *analysis.Pass, methods map[*types.Func]*ast.FuncDecl, r registration) []registration {
+	if r.serviceType == nil || r.v0 {
+		return nil
+	}
+	selection := types.NewMethodSet(r.serviceType).Lookup(nil, "ProvideService")
+	if selection == nil {
+		return nil
+	}
+	declaration, found := methods[selection.Obj().(*types.Func)]
+	if !found {
+		return []registration{{call: r.call}}
+	}
+
+	var provided []registration
+	ast.Inspect(declaration.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				for _, service := range providedExpressions(pass, result) {
+					serviceType := pass.TypesInfo.TypeOf(service)
+					if isUntypedNil(serviceType) {
+						continue
+					}
+					// A service returned as any could be of any type
+					if iface, ok := serviceType.Underlying().(*types.Interface); ok && iface.Empty() {
+						serviceType = nil
+					}
+					provided = append(provided, registration{serviceType: serviceType, call: r.call})
+				}
+			}
+		}
+		return true
+	})
+	return provided
+}
+
+// providedExpressions returns the elements of a ProvidedServices literal, or the expression itself.
+func providedExpressions(pass *analysis.Pass, result ast.Expr) []ast.Expr {
+	literal, ok := ast.Unparen(result).(*ast.CompositeLit)
+	if !ok {
+		return []ast.Expr{result}
+	}
+	named, ok := pass.TypesInfo.TypeOf(literal).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != v1Path || named.Obj().Name() != "ProvidedServices" {
+		return []ast.Expr{result}
+	}
+	return literal.Elts
+}
+
+func isUntypedNil(typ types.Type) bool {
+	basic, ok := typ.(*types.Basic)
+	return ok && basic.Kind() == types.UntypedNil
+}
+
+// checkInjectedValue reports struct values passed to Inject and returns the registration.
+func checkInjectedValue(pass *analysis.Pass, call *ast.CallExpr, argument ast.Expr) registration {
+	argumentType := pass.TypesInfo.TypeOf(argument)
+	if argumentType != nil {
+		if _, isStruct := argumentType.Underlying().(*types.Struct); isStruct {
+			pass.Reportf(argument.Pos(), "Inject of non-pointer value of type %s: Init methods with pointer receivers will not run and Get[*%s] will not find it", typeString(pass, argumentType), typeString(pass, argumentType))
+		}
+	}
+	return registration{serviceType: argumentType, call: call}
+}
+
+// checkInitParameters reports Init parameters of the registered type that are not injected.
+func checkInitParameters(pass *analysis.Pass, registrations []registration, r registration) {
+	if r.serviceType == nil {
+		return
+	}
+	selection := types.NewMethodSet(r.serviceType).Lookup(nil, "Init")
+	if selection == nil {
+		return
+	}
+	params := selection.Obj().Type().(*types.Signature).Params()
+	for index := 0; index < params.Len(); index++ {
+		parameterType := params.At(index).Type()
+		if isInstanceMarker(parameterType) || isSatisfied(registrations, parameterType, r.v0) {
+			continue
+		}
+		position := r.call.Pos()
+		if method := selection.Obj(); method.Pkg() == pass.Pkg {
+			position = params.At(index).Pos()
+		}
+		pass.Reportf(position, "Init of %s has parameter of type %s that is not injected in package %s", typeString(pass, r.serviceType), typeString(pass, parameterType), pass.Pkg.Name())
+	}
+}
+
+// isSatisfied reports whether a registered service can be resolved as the target type.
+func isSatisfied(registrations []registration, targetType types.Type, v0 bool) bool {
+	if providedTypes[types.TypeString(targetType, nil)] {
+		return true
+	}
+	iface, isInterface := targetType.Underlying().(*types.Interface)
+	for _, r := range registrations {
+		// Registrations of unknown type could provide anything
+		if r.serviceType == nil {
+			return true
+		}
+		if types.Identical(r.serviceType, targetType) {
+			return true
+		}
+		if isInterface && types.Implements(r.serviceType, iface) {
+			return true
+		}
+		// v0 resolves T from a registered *T
+		if v0 || r.v0 {
+			if pointer, ok := r.serviceType.(*types.Pointer); ok && types.Identical(pointer.Elem(), targetType) {
+				return true
+			}
+		}
+		// Services registered through an interface can satisfy any type at runtime
+		if _, registeredInterface := r.serviceType.Underlying().(*types.Interface); registeredInterface && !isInterface {
+			if types.AssignableTo(targetType, r.serviceType) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isInstanceMarker reports whether the type is one of the new-instance marker types.
+func isInstanceMarker(parameterType types.Type) bool {
+	named, ok := parameterType.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	switch named.Obj().Pkg().Path() + "." + named.Obj().Name() {
+	case v0Path + ".InitializeNewInstanceTo", v1Path + ".InstanceCreationMode":
+		return true
+	}
+	return false
+}
+
+func isPointerOrInterface(typ types.Type) bool {
+	if typ == nil {
+		return true
+	}
+	switch typ.Underlying().(type) {
+	case *types.Pointer, *types.Interface:
+		return true
+	}
+	return false
+}
+
+// typeArgument returns the first explicit or inferred type argument of a generic call.
+func typeArgument(pass *analysis.Pass, call *ast.CallExpr) types.Type {
+	var identifier *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.IndexExpr:
+		identifier = calleeIdent(fun.X)
+	case *ast.IndexListExpr:
+		identifier = calleeIdent(fun.X)
+	default:
+		identifier = calleeIdent(fun)
+	}
+	if identifier == nil {
+		return nil
+	}
+	instance, ok := pass.TypesInfo.Instances[identifier]
+	if !ok || instance.TypeArgs.Len() == 0 {
+		return nil
+	}
+	return instance.TypeArgs.At(0)
+}
+
+func calleeIdent(expression ast.Expr) *ast.Ident {
+	switch expression := expression.(type) {
+	case *ast.Ident:
+		return expression
+	case *ast.SelectorExpr:
+		return expression.Sel
+	}
+	return nil
+}
+
+func typeString(pass *analysis.Pass, typ types.Type) string {
+	return types.TypeString(typ, types.RelativeTo(pass.Pkg))
+}
@@ -0,0 +1,13 @@
+package siocvet_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/sergiodii/sioc/analysis/siocvet"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), siocvet.Analyzer, "v1app", "v0app", "mixed", "lookups", "providers")
+}
@@ -0,0 +1,19 @@
+// Package sioc is a stub of github.com/sergiodii/sioc/v0 for analyzer tests.
+package sioc
+
+import v1 "github.com/sergiodii/sioc/v1"
+
+type InitializeNewInstanceTo string
+
+type InjectorSnapshot struct{}
+
+func Start()                   {}
+func Register(cls interface{}) {}
+func Init()                    {}
+func Get[T any]() T {
+	var service T
+	return service
+}
+func UseContainer(serviceContainer v1.ServiceContainer) {}
+func Snapshot() *InjectorSnapshot                       { return nil }
+func Restore(snapshot *InjectorSnapshot)                {}
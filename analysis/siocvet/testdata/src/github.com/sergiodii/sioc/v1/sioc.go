@@ -0,0 +1,20 @@
+// Package sioc is a stub of github.com/sergiodii/sioc/v1 for analyzer tests.
+package sioc
+
+type ServiceContainer interface{}
+
+type InstanceCreationMode string
+
+type ProvidedServices []any
+
+func NewContainer() ServiceContainer                                                  { return nil }
+func Inject(serviceInstance any, serviceContainer ServiceContainer)                   {}
+func InjectIf(condition bool, serviceInstance any, serviceContainer ServiceContainer) {}
+func InjectAs[T any](serviceInstance T, serviceContainer ServiceContainer)            {}
+func InjectPooled[T any](newService func() T, serviceContainer ServiceContainer)      {}
+func BindConfig[T any](serviceContainer ServiceContainer) (*T, error)                 { return nil, nil }
+func Get[T any](serviceContainer ServiceContainer) T {
+	var service T
+	return service
+}
+func Init(serviceContainer ServiceContainer) {}
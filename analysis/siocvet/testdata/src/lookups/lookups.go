@@ -0,0 +1,10 @@
+package lookups
+
+import sioc "github.com/sergiodii/sioc/v1"
+
+type Handler struct{}
+
+// Packages that do not register services resolve from containers built elsewhere.
+func handle(container sioc.ServiceContainer) *Handler {
+	return sioc.Get[*Handler](container)
+}
@@ -0,0 +1,24 @@
+package mixed
+
+import (
+	v0 "github.com/sergiodii/sioc/v0"
+	sioc "github.com/sergiodii/sioc/v1"
+)
+
+type Service struct{}
+
+func setup() {
+	container := sioc.NewContainer()
+	sioc.Inject(&Service{}, container)
+
+	v0.Register(&Service{}) // want `v0 global API sioc.Register used in a package that uses sioc/v1`
+	v0.Get[*Service]()      // want `v0 global API sioc.Get used in a package that uses sioc/v1`
+}
+
+// The bridge functions are meant to be used alongside sioc/v1.
+func bridge() {
+	container := sioc.NewContainer()
+	v0.UseContainer(container)
+	snapshot := v0.Snapshot()
+	v0.Restore(snapshot)
+}
@@ -0,0 +1,52 @@
+package providers
+
+import sioc "github.com/sergiodii/sioc/v1"
+
+type Buffer struct{}
+
+type Client struct{}
+
+type Metrics struct{}
+
+type Tracer struct{}
+
+type Missing struct{}
+
+// ClientProvider provides a single service.
+type ClientProvider struct{}
+
+func (p *ClientProvider) ProvideService() any {
+	return &Client{}
+}
+
+// TelemetryProvider provides several services and another provider.
+type TelemetryProvider struct{}
+
+func (p *TelemetryProvider) ProvideService() any {
+	return sioc.ProvidedServices{&Metrics{}, &TracerProvider{}}
+}
+
+type TracerProvider struct{}
+
+func (p *TracerProvider) ProvideService() any {
+	return &Tracer{}
+}
+
+type Handler struct{}
+
+func (h *Handler) Init(buffer *Buffer, client *Client, metrics *Metrics, tracer *Tracer, missing *Missing) { // want `Init of \*Handler has parameter of type \*Missing that is not injected in package providers`
+}
+
+func setup() {
+	container := sioc.NewContainer()
+	sioc.InjectPooled(func() *Buffer { return &Buffer{} }, container)
+	sioc.Inject(&ClientProvider{}, container)
+	sioc.Inject(&TelemetryProvider{}, container)
+	sioc.Inject(&Handler{}, container)
+
+	sioc.Get[*Buffer](container)
+	sioc.Get[*Client](container)
+	sioc.Get[*Metrics](container)
+	sioc.Get[*Tracer](container)
+	sioc.Get[*Missing](container) // want `Get\[\*Missing\]: no service of this type is injected in package providers`
+}
@@ -0,0 +1,21 @@
+package v0app
+
+import sioc "github.com/sergiodii/sioc/v0"
+
+type Repository struct{}
+
+type Service struct{}
+
+func (s *Service) Init(_ sioc.InitializeNewInstanceTo, repository *Repository) {}
+
+func setup() {
+	sioc.Start()
+	sioc.Register(&Repository{})
+	sioc.Register(&Service{})
+	sioc.Register(Repository{}) // want `Register of non-pointer value of type Repository fails at runtime`
+
+	sioc.Get[Repository]()
+	sioc.Get[*Service]()
+	sioc.Get[*string]() // want `Get\[\*string\]: no service of this type is injected in package v0app`
+	sioc.Init()
+}
@@ -0,0 +1,51 @@
+package v1app
+
+import (
+	"context"
+
+	sioc "github.com/sergiodii/sioc/v1"
+)
+
+type Logger interface {
+	Log(message string)
+}
+
+type ConsoleLogger struct{}
+
+func (c *ConsoleLogger) Log(message string) {}
+
+type Database struct{}
+
+type Cache struct{}
+
+type Config struct {
+	URL string
+}
+
+type UserService struct{}
+
+func (u *UserService) Init(logger Logger, database *Database, cache *Cache, config *Config, ctx context.Context) { // want `Init of \*UserService has parameter of type \*Cache that is not injected in package v1app`
+}
+
+type Reports struct{}
+
+func (r *Reports) Init(_ sioc.InstanceCreationMode, database *Database) {}
+
+func setup() {
+	container := sioc.NewContainer()
+	sioc.Inject(&ConsoleLogger{}, container)
+	sioc.Inject(&Database{}, container)
+	sioc.Inject(&UserService{}, container)
+	sioc.Inject(&Reports{}, container)
+	sioc.Inject(Database{}, container)      // want `Inject of non-pointer value of type Database`
+	sioc.InjectIf(true, Cache{}, container) // want `Inject of non-pointer value of type Cache`
+	sioc.Inject("name", container)
+	sioc.BindConfig[Config](container)
+
+	sioc.Get[*UserService](container)
+	sioc.Get[Logger](container)
+	sioc.Get[*Config](container)
+	sioc.Get[context.Context](container)
+	sioc.Get[*Cache](container) // want `Get\[\*Cache\]: no service of this type is injected in package v1app`
+	sioc.Init(container)
+}
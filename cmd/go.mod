@@ -0,0 +1,20 @@
+module github.com/sergiodii/sioc/cmd
+
+go 1.22.0
+
+require (
+	github.com/sergiodii/sioc v0.0.0-00010101000000-000000000000
+	github.com/sergiodii/sioc/analysis v0.0.0-00010101000000-000000000000
+	golang.org/x/tools v0.30.0
+)
+
+require (
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/sergiodii/sioc => ../
+	github.com/sergiodii/sioc/analysis => ../analysis
+)
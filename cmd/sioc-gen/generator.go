@@ -0,0 +1,431 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// generatorConfig describes a single sioc-gen run.
+type generatorConfig struct {
+	// Dir is the directory the package pattern is resolved from.
+	Dir string
+	// Pattern selects the package to generate wiring for.
+	Pattern string
+	// Output is the name of the generated file, written next to the package sources.
+	Output string
+	// FuncName is the name of the generated constructor.
+	FuncName string
+	// Overlay replaces file contents when loading the package, as in packages.Config.
+	Overlay map[string][]byte
+}
+
+// service is a type the generated code constructs and registers.
+type service struct {
+	key         string
+	serviceType types.Type
+	provider    *types.Func
+	init        *types.Func
+	position    token.Position
+
+	dependencies []*service
+	variable     string
+}
+
+// name returns a readable name for error messages.
+func (s *service) name() string {
+	return s.key
+}
+
+// generate loads the package and returns the path and contents of the wiring file.
+func generate(config generatorConfig) (string, []byte, error) {
+	loadConfig := &packages.Config{
+		Mode:    packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes,
+		Dir:     config.Dir,
+		Overlay: config.Overlay,
+	}
+	loaded, err := packages.Load(loadConfig, config.Pattern)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(loaded) != 1 {
+		return "", nil, fmt.Errorf("pattern %q matched %d packages, expected 1", config.Pattern, len(loaded))
+	}
+	pkg := loaded[0]
+	if len(pkg.Errors) > 0 {
+		var loadErrors []error
+		for _, packageError := range pkg.Errors {
+			loadErrors = append(loadErrors, packageError)
+		}
+		return "", nil, errors.Join(loadErrors...)
+	}
+	if len(pkg.GoFiles) == 0 {
+		return "", nil, fmt.Errorf("package %s has no Go files", pkg.PkgPath)
+	}
+
+	outputPath := filepath.Join(filepath.Dir(pkg.GoFiles[0]), config.Output)
+	services, err := collectServices(pkg, outputPath)
+	if err != nil {
+		return "", nil, err
+	}
+	// Cycles are looked for even when some dependencies are missing, so that every
+	// problem of the graph is reported in a single run.
+	resolveErr := resolveDependencies(pkg.Types, services)
+	ordered, sortErr := sortServices(services)
+	if err := errors.Join(resolveErr, sortErr); err != nil {
+		return "", nil, err
+	}
+
+	source, err := render(pkg.Types, config.FuncName, ordered)
+	if err != nil {
+		return "", nil, err
+	}
+	return outputPath, source, nil
+}
+
+// collectServices finds the providers (New* functions) and the types with Init methods.
+func collectServices(pkg *packages.Package, outputPath string) ([]*service, error) {
+	scope := pkg.Types.Scope()
+	servicesByKey := map[string]*service{}
+	var collectErrors []error
+
+	for _, name := range scope.Names() {
+		function, ok := scope.Lookup(name).(*types.Func)
+		if !ok || !strings.HasPrefix(name, "New") {
+			continue
+		}
+		position := pkg.Fset.Position(function.Pos())
+		if position.Filename == outputPath {
+			continue
+		}
+		serviceType, ok := providedType(function)
+		if !ok {
+			continue
+		}
+
+		key := types.TypeString(serviceType, types.RelativeTo(pkg.Types))
+		if existing, found := servicesByKey[key]; found {
+			collectErrors = append(collectErrors, fmt.Errorf("%s: %s provides %s, already provided by %s", position, name, key, existing.provider.Name()))
+			continue
+		}
+		servicesByKey[key] = &service{key: key, serviceType: serviceType, provider: function, position: position}
+	}
+
+	for _, name := range scope.Names() {
+		typeName, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || typeName.IsAlias() {
+			continue
+		}
+		named, ok := typeName.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 || types.IsInterface(named) {
+			continue
+		}
+		initMethod, ok := initMethodOf(pkg.Types, named)
+		if !ok {
+			continue
+		}
+
+		pointerType := types.NewPointer(named)
+		key := types.TypeString(pointerType, types.RelativeTo(pkg.Types))
+		if provided, found := servicesByKey[key]; found {
+			provided.init = initMethod
+			continue
+		}
+		valueKey := types.TypeString(named, types.RelativeTo(pkg.Types))
+		if provided, found := servicesByKey[valueKey]; found {
+			provided.init = initMethod
+			continue
+		}
+		servicesByKey[key] = &service{
+			key:         key,
+			serviceType: pointerType,
+			init:        initMethod,
+			position:    pkg.Fset.Position(typeName.Pos()),
+		}
+	}
+
+	services := make([]*service, 0, len(servicesByKey))
+	for _, s := range servicesByKey {
+		services = append(services, s)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].key < services[j].key
+	})
+	return services, errors.Join(collectErrors...)
+}
+
+// providedType returns the type built by a provider: a function returning a named type
+// or a pointer to one, optionally followed by an error.
+func providedType(function *types.Func) (types.Type, bool) {
+	signature := function.Type().(*types.Signature)
+	if signature.TypeParams().Len() > 0 || signature.Variadic() {
+		return nil, false
+	}
+	results := signature.Results()
+	if results.Len() == 0 || results.Len() > 2 {
+		return nil, false
+	}
+	if results.Len() == 2 && !isErrorType(results.At(1).Type()) {
+		return nil, false
+	}
+
+	serviceType := results.At(0).Type()
+	named := serviceType
+	if pointer, ok := serviceType.(*types.Pointer); ok {
+		named = pointer.Elem()
+	}
+	if _, ok := named.(*types.Named); !ok || isErrorType(serviceType) {
+		return nil, false
+	}
+	return serviceType, true
+}
+
+// initMethodOf returns the Init method of *T, if it returns nothing or an error.
+func initMethodOf(pkg *types.Package, named *types.Named) (*types.Func, bool) {
+	selection := types.NewMethodSet(types.NewPointer(named)).Lookup(pkg, "Init")
+	if selection == nil {
+		return nil, false
+	}
+	method := selection.Obj().(*types.Func)
+	signature := method.Type().(*types.Signature)
+	if signature.Variadic() {
+		return nil, false
+	}
+	results := signature.Results()
+	if results.Len() > 1 || (results.Len() == 1 && !isErrorType(results.At(0).Type())) {
+		return nil, false
+	}
+	return method, true
+}
+
+func isErrorType(typ types.Type) bool {
+	return types.Identical(typ, types.Universe.Lookup("error").Type())
+}
+
+func hasErrorResult(function *types.Func) bool {
+	if function == nil {
+		return false
+	}
+	results := function.Type().(*types.Signature).Results()
+	return results.Len() > 0 && isErrorType(results.At(results.Len()-1).Type())
+}
+
+// parameterTypes returns the parameters of the provider followed by those of Init.
+func (s *service) parameterTypes() []types.Type {
+	var parameters []types.Type
+	for _, function := range []*types.Func{s.provider, s.init} {
+		if function == nil {
+			continue
+		}
+		params := function.Type().(*types.Signature).Params()
+		for index := 0; index < params.Len(); index++ {
+			parameters = append(parameters, params.At(index).Type())
+		}
+	}
+	return parameters
+}
+
+// resolveDependencies matches every parameter to a service, by identical type or by
+// being the only service implementing an interface parameter.
+func resolveDependencies(pkg *types.Package, services []*service) error {
+	var resolveErrors []error
+	for _, s := range services {
+		for _, parameterType := range s.parameterTypes() {
+			dependency, err := findDependency(pkg, services, parameterType)
+			if err != nil {
+				resolveErrors = append(resolveErrors, fmt.Errorf("%s: %s: %w", s.position, s.name(), err))
+				continue
+			}
+			s.dependencies = append(s.dependencies, dependency)
+		}
+	}
+	return errors.Join(resolveErrors...)
+}
+
+func findDependency(pkg *types.Package, services []*service, parameterType types.Type) (*service, error) {
+	parameterName := types.TypeString(parameterType, types.RelativeTo(pkg))
+	for _, candidate := range services {
+		if types.Identical(candidate.serviceType, parameterType) {
+			return candidate, nil
+		}
+	}
+
+	iface, ok := parameterType.Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("unresolvable dependency %s: no provider or Init type", parameterName)
+	}
+	var matches []*service
+	for _, candidate := range services {
+		if types.Implements(candidate.serviceType, iface) {
+			matches = append(matches, candidate)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("unresolvable dependency %s: no service implements it", parameterName)
+	case 1:
+		return matches[0], nil
+	default:
+		var names []string
+		for _, match := range matches {
+			names = append(names, match.name())
+		}
+		return nil, fmt.Errorf("ambiguous dependency %s: implemented by %s", parameterName, strings.Join(names, ", "))
+	}
+}
+
+// sortServices orders the services so that every service comes after its dependencies.
+func sortServices(services []*service) ([]*service, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[*service]int{}
+	var ordered []*service
+	var path []*service
+
+	var visit func(s *service) error
+	visit = func(s *service) error {
+		switch state[s] {
+		case visited:
+			return nil
+		case visiting:
+			var cycle []string
+			for index := len(path) - 1; index >= 0; index-- {
+				cycle = append([]string{path[index].name()}, cycle...)
+				if path[index] == s {
+					break
+				}
+			}
+			return fmt.Errorf("%s: dependency cycle: %s -> %s", s.position, strings.Join(cycle, " -> "), s.name())
+		}
+		state[s] = visiting
+		path = append(path, s)
+		for _, dependency := range s.dependencies {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[s] = visited
+		ordered = append(ordered, s)
+		return nil
+	}
+
+	for _, s := range services {
+		if err := visit(s); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// render writes the wiring function for the ordered services.
+func render(pkg *types.Package, funcName string, services []*service) ([]byte, error) {
+	assignVariables(services)
+	qualifier := types.RelativeTo(pkg)
+
+	needsFmt := false
+	for _, s := range services {
+		needsFmt = needsFmt || hasErrorResult(s.provider) || hasErrorResult(s.init)
+	}
+
+	var body bytes.Buffer
+	for _, s := range services {
+		providerArguments, initArguments := s.arguments()
+		switch {
+		case s.provider == nil:
+			elem := s.serviceType.(*types.Pointer).Elem()
+			fmt.Fprintf(&body, "\t%s := &%s{}\n", s.variable, types.TypeString(elem, qualifier))
+		case hasErrorResult(s.provider):
+			fmt.Fprintf(&body, "\t%s, err := %s(%s)\n", s.variable, s.provider.Name(), providerArguments)
+			fmt.Fprintf(&body, "\tif err != nil {\n\t\treturn nil, fmt.Errorf(\"%s: %%w\", err)\n\t}\n", s.provider.Name())
+		default:
+			fmt.Fprintf(&body, "\t%s := %s(%s)\n", s.variable, s.provider.Name(), providerArguments)
+		}
+
+		injectOptions := ""
+		if s.init != nil {
+			injectOptions = ", sioc.Initialized()"
+			call := fmt.Sprintf("%s.Init(%s)", s.variable, initArguments)
+			if hasErrorResult(s.init) {
+				fmt.Fprintf(&body, "\tif err := %s; err != nil {\n\t\treturn nil, fmt.Errorf(\"(%s).Init: %%w\", err)\n\t}\n", call, s.name())
+			} else {
+				fmt.Fprintf(&body, "\t%s\n", call)
+			}
+		}
+		fmt.Fprintf(&body, "\tsioc.Inject(%s, container%s)\n\n", s.variable, injectOptions)
+	}
+
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "// Code generated by sioc-gen. DO NOT EDIT.\n\npackage %s\n\n", pkg.Name())
+	source.WriteString("import (\n")
+	if needsFmt {
+		source.WriteString("\t\"fmt\"\n\n")
+	}
+	source.WriteString("\tsioc \"github.com/sergiodii/sioc/v1\"\n)\n\n")
+	fmt.Fprintf(&source, "// %s builds every service of the package in dependency order and registers\n", funcName)
+	source.WriteString("// it in a new container. Init methods are called here, so sioc.Init skips them.\n")
+	fmt.Fprintf(&source, "func %s(options ...sioc.ContainerOption) (sioc.ServiceContainer, error) {\n", funcName)
+	source.WriteString("\tcontainer := sioc.NewContainer(options...)\n\n")
+	source.Write(body.Bytes())
+	source.WriteString("\treturn container, nil\n}\n")
+
+	return format.Source(source.Bytes())
+}
+
+// arguments returns the variables passed to the provider and to Init.
+func (s *service) arguments() (string, string) {
+	var providerArguments, initArguments []string
+	providerParams := 0
+	if s.provider != nil {
+		providerParams = s.provider.Type().(*types.Signature).Params().Len()
+	}
+	for index, dependency := range s.dependencies {
+		if index < providerParams {
+			providerArguments = append(providerArguments, dependency.variable)
+		} else {
+			initArguments = append(initArguments, dependency.variable)
+		}
+	}
+	return strings.Join(providerArguments, ", "), strings.Join(initArguments, ", ")
+}
+
+// assignVariables gives every service a unique local variable name derived from its type.
+func assignVariables(services []*service) {
+	used := map[string]bool{"container": true, "err": true, "fmt": true, "sioc": true, "options": true}
+	for _, s := range services {
+		base := variableName(s.serviceType)
+		candidate := base
+		for suffix := 2; used[candidate] || token.IsKeyword(candidate); suffix++ {
+			candidate = fmt.Sprintf("%s%d", base, suffix)
+		}
+		used[candidate] = true
+		s.variable = candidate
+	}
+}
+
+func variableName(serviceType types.Type) string {
+	if pointer, ok := serviceType.(*types.Pointer); ok {
+		serviceType = pointer.Elem()
+	}
+	name := serviceType.(*types.Named).Obj().Name()
+	runes := []rune(name)
+	for index := 0; index < len(runes) && unicode.IsUpper(runes[index]); index++ {
+		if index > 0 && index+1 < len(runes) && unicode.IsLower(runes[index+1]) {
+			break
+		}
+		runes[index] = unicode.ToLower(runes[index])
+	}
+	return string(runes)
+}
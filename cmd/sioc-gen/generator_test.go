@@ -0,0 +1,129 @@
+package main
+
+import (
+	"go/types"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+
+	// The generated code imports the library, so the module must require it.
+	_ "github.com/sergiodii/sioc/v1"
+)
+
+func generateTestdata(t *testing.T, name string, overlay map[string][]byte) (string, string, error) {
+	t.Helper()
+	path, source, err := generate(generatorConfig{
+		Pattern:  "./testdata/" + name,
+		Output:   "sioc_gen.go",
+		FuncName: "NewServiceContainer",
+		Overlay:  overlay,
+	})
+	return path, string(source), err
+}
+
+// TestGenerateOrdersServices tests that services are built after their dependencies
+func TestGenerateOrdersServices(t *testing.T) {
+	path, source, err := generateTestdata(t, "app", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedPath, _ := filepath.Abs("testdata/app/sioc_gen.go")
+	if path != expectedPath {
+		t.Errorf("Expected output %s, got %s", expectedPath, path)
+	}
+	if !strings.HasPrefix(source, "// Code generated by sioc-gen. DO NOT EDIT.") {
+		t.Error("Expected the generated header")
+	}
+
+	steps := []string{
+		"config, err := NewConfig()",
+		"memoryStore := NewMemoryStore(config)",
+		"userService := &UserService{}",
+		"userService.Init(memoryStore)",
+		"httpServer.Init(userService, config)",
+		"sioc.Inject(httpServer, container, sioc.Initialized())",
+	}
+	previous := -1
+	for _, step := range steps {
+		index := strings.Index(source, step)
+		if index < 0 {
+			t.Fatalf("Expected generated code to contain %q:\n%s", step, source)
+		}
+		if index < previous {
+			t.Errorf("Expected %q to come after its dependencies:\n%s", step, source)
+		}
+		previous = index
+	}
+}
+
+// TestGenerateTypeChecks tests that the generated file compiles with the package
+func TestGenerateTypeChecks(t *testing.T) {
+	path, source, err := generateTestdata(t, "app", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	loaded, err := packages.Load(&packages.Config{
+		Mode:    packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedDeps,
+		Overlay: map[string][]byte{path: []byte(source)},
+	}, "./testdata/app")
+	if err != nil {
+		t.Fatalf("Failed to load package: %v", err)
+	}
+	for _, packageError := range loaded[0].Errors {
+		t.Errorf("Generated code does not type-check: %v", packageError)
+	}
+
+	constructor, ok := loaded[0].Types.Scope().Lookup("NewServiceContainer").(*types.Func)
+	if !ok {
+		t.Fatal("Expected NewServiceContainer to be declared")
+	}
+	if signature := constructor.Type().String(); !strings.HasSuffix(signature, "(github.com/sergiodii/sioc/v1.ServiceContainer, error)") {
+		t.Errorf("Unexpected constructor signature %s", signature)
+	}
+
+	// Running the generator again must ignore its previous output
+	_, regenerated, err := generateTestdata(t, "app", map[string][]byte{path: []byte(source)})
+	if err != nil {
+		t.Fatalf("Unexpected error when regenerating: %v", err)
+	}
+	if regenerated != source {
+		t.Error("Expected regeneration to be stable")
+	}
+}
+
+// TestGenerateReportsGraphErrors tests that every graph problem is reported together
+func TestGenerateReportsGraphErrors(t *testing.T) {
+	_, _, err := generateTestdata(t, "broken", nil)
+	if err == nil {
+		t.Fatal("Expected the broken graph to be rejected")
+	}
+
+	for _, expected := range []string{
+		"*Reporter: unresolvable dependency Clock",
+		"*Reporter: unresolvable dependency *Missing",
+		"dependency cycle: *Ping -> *Pong -> *Ping",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to contain %q, got:\n%v", expected, err)
+		}
+	}
+}
+
+// TestVariableName tests local variable naming from type names
+func TestVariableName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Config":     "config",
+		"HTTPServer": "httpServer",
+		"DB":         "db",
+		"userCache":  "userCache",
+	} {
+		named := types.NewNamed(types.NewTypeName(0, nil, name, nil), types.NewStruct(nil, nil), nil)
+		if got := variableName(types.NewPointer(named)); got != expected {
+			t.Errorf("variableName(%s) = %s, expected %s", name, got, expected)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Command sioc-gen generates compile-time wiring for a package that declares sioc v1 services.
+//
+// Providers are the package's New* functions returning a named type (or a pointer to one),
+// optionally followed by an error. Types with an Init method are built with their provider,
+// or as &T{} when they have none, and Init is called with its dependencies. Parameters are
+// matched by type, or by the single service implementing an interface parameter. Missing,
+// ambiguous and cyclic dependencies are reported at generate time.
+//
+// The generated constructor returns a regular v1 ServiceContainer, so sioc.Get keeps working:
+//
+//	//go:generate go run github.com/sergiodii/sioc/cmd/sioc-gen
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	output := flag.String("output", "sioc_gen.go", "name of the generated file, written in the package directory")
+	funcName := flag.String("func", "NewServiceContainer", "name of the generated constructor")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: sioc-gen [flags] [package]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	pattern := "."
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if flag.NArg() == 1 {
+		pattern = flag.Arg(0)
+	}
+
+	path, source, err := generate(generatorConfig{Pattern: pattern, Output: *output, FuncName: *funcName})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sioc-gen: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(path, source, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "sioc-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
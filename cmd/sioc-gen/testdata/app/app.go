@@ -0,0 +1,49 @@
+package app
+
+import "errors"
+
+type Config struct {
+	DSN string
+}
+
+func NewConfig() (*Config, error) {
+	return &Config{DSN: "memory"}, nil
+}
+
+type Store interface {
+	Find(id string) string
+}
+
+type MemoryStore struct {
+	config *Config
+}
+
+func NewMemoryStore(config *Config) *MemoryStore {
+	return &MemoryStore{config: config}
+}
+
+func (ms *MemoryStore) Find(id string) string {
+	return ms.config.DSN + ":" + id
+}
+
+type UserService struct {
+	Store Store
+}
+
+func (us *UserService) Init(store Store) error {
+	if store == nil {
+		return errors.New("missing store")
+	}
+	us.Store = store
+	return nil
+}
+
+type HTTPServer struct {
+	Users  *UserService
+	Config *Config
+}
+
+func (hs *HTTPServer) Init(users *UserService, config *Config) {
+	hs.Users = users
+	hs.Config = config
+}
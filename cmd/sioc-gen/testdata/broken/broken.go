@@ -0,0 +1,19 @@
+package broken
+
+type Clock interface {
+	Now() int64
+}
+
+type Missing struct{}
+
+type Reporter struct{}
+
+func (r *Reporter) Init(_ Clock, _ *Missing) {}
+
+type Ping struct{}
+
+func (p *Ping) Init(_ *Pong) {}
+
+type Pong struct{}
+
+func (p *Pong) Init(_ *Ping) {}
@@ -0,0 +1,64 @@
+// Command sioc-migrate rewrites code that uses the v0 global API (sioc.Start, Register,
+// Get[T]() and Init()) to an explicit sioc/v1 container.
+//
+// Each migrated package gets a package-level container variable, declared in the file that
+// called Start (or the first file using the API), and every call is rewritten to use it:
+//
+//	sioc.Register(&Service{})  ->  sioc.Inject(&Service{}, container)
+//	sioc.Get[*Service]()       ->  sioc.Get[*Service](container)
+//	sioc.Init()                ->  sioc.Init(container)
+//
+// InitializeNewInstanceTo parameters become InstanceCreationMode. v1 does not resolve a value
+// type from its registered pointer as v0 did, so Get of a value type is rewritten to
+// dereference the pointer:
+//
+//	sioc.Get[Service]()        ->  *sioc.Get[*Service](container)
+//
+// Anything that cannot be converted is reported on stderr, and the command then exits with
+// status 1. Findings marked as errors leave code that does not compile against v1.
+//
+// Without -w, the files that would change are listed and nothing is written.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	write := flag.Bool("w", false, "write the migrated files instead of listing them")
+	container := flag.String("container", "container", "name of the package-level container variable")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: sioc-migrate [flags] [path ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	migrated, findings, err := migrate(migrateConfig{Paths: paths, Container: *container})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sioc-migrate: %v\n", err)
+		os.Exit(1)
+	}
+	for _, file := range migrated {
+		if !*write {
+			fmt.Println(file.Path)
+			continue
+		}
+		if err := os.WriteFile(file.Path, file.Source, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "sioc-migrate: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	for _, f := range findings {
+		fmt.Fprintln(os.Stderr, f)
+	}
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
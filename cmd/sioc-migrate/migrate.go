@@ -0,0 +1,591 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	rootPath = "github.com/sergiodii/sioc"
+	v0Path   = "github.com/sergiodii/sioc/v0"
+	v1Path   = "github.com/sergiodii/sioc/v1"
+)
+
+// migrateConfig describes a single sioc-migrate run.
+type migrateConfig struct {
+	// Paths are the files and directories to migrate. Directories are walked recursively.
+	Paths []string
+	// Container is the name of the package-level container variable the rewritten calls use.
+	Container string
+}
+
+// migratedFile is a file whose source was rewritten.
+type migratedFile struct {
+	Path   string
+	Source []byte
+}
+
+// finding is something the migration could not convert, or that needs a manual check.
+type finding struct {
+	Position token.Position
+	Message  string
+	// Error is set when the migrated code refers to something v1 does not have, so it no
+	// longer compiles until it is fixed by hand.
+	Error bool
+}
+
+func (f finding) String() string {
+	if f.Error {
+		return fmt.Sprintf("%s: error: %s", f.Position, f.Message)
+	}
+	return fmt.Sprintf("%s: %s", f.Position, f.Message)
+}
+
+// edit replaces the source between two offsets.
+type edit struct {
+	start, end  int
+	replacement string
+}
+
+// sourceFile is a parsed file of the package being migrated.
+type sourceFile struct {
+	path   string
+	source []byte
+	syntax *ast.File
+}
+
+// fileMigration is the outcome of migrating a single file.
+type fileMigration struct {
+	edits    []edit
+	findings []finding
+
+	// target is the name the file uses for the v1 package.
+	target string
+	// usesContainer is set when a rewritten call refers to the container variable.
+	usesContainer bool
+	starts        bool
+	registers     bool
+}
+
+// migrate rewrites the v0 global API in the configured paths to a v1 container.
+func migrate(config migrateConfig) ([]migratedFile, []finding, error) {
+	directories, err := goFilesByDirectory(config.Paths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var migrated []migratedFile
+	var findings []finding
+	for _, directory := range sortedKeys(directories) {
+		fset := token.NewFileSet()
+		packages := make(map[string][]*sourceFile)
+		for _, path := range directories[directory] {
+			source, err := os.ReadFile(path)
+			if err != nil {
+				return nil, nil, err
+			}
+			syntax, err := parser.ParseFile(fset, path, source, parser.ParseComments)
+			if err != nil {
+				return nil, nil, err
+			}
+			packages[syntax.Name.Name] = append(packages[syntax.Name.Name], &sourceFile{path: path, source: source, syntax: syntax})
+		}
+		for _, name := range sortedKeys(packages) {
+			files, packageFindings, err := migratePackage(fset, packages[name], config.Container)
+			if err != nil {
+				return nil, nil, err
+			}
+			migrated = append(migrated, files...)
+			findings = append(findings, packageFindings...)
+		}
+	}
+	return migrated, findings, nil
+}
+
+// migratePackage rewrites the files of one package and declares the container in one of them.
+func migratePackage(fset *token.FileSet, files []*sourceFile, container string) ([]migratedFile, []finding, error) {
+	var findings []finding
+	for _, file := range files {
+		if !importsLegacy(file.syntax) {
+			continue
+		}
+		if position, found := findDeclaration(file.syntax, container); found {
+			findings = append(findings, finding{
+				Position: fset.Position(position),
+				Message:  fmt.Sprintf("identifier %q is already used in package %s; rerun with -container to pick another name", container, file.syntax.Name.Name),
+			})
+			return nil, findings, nil
+		}
+	}
+
+	declaredTypes := make(map[string]ast.Expr)
+	for _, file := range files {
+		for _, declaration := range file.syntax.Decls {
+			if genDecl, ok := declaration.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
+				for _, spec := range genDecl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					declaredTypes[typeSpec.Name.Name] = typeSpec.Type
+				}
+			}
+		}
+	}
+
+	migrations := make(map[*sourceFile]*fileMigration)
+	var declaringFile *sourceFile
+	registers, usesContainer := false, false
+	for _, file := range files {
+		if !importsLegacy(file.syntax) {
+			continue
+		}
+		migration := migrateFile(fset, file.syntax, file.source, container, declaredTypes)
+		migrations[file] = migration
+		findings = append(findings, migration.findings...)
+		registers = registers || migration.registers
+		usesContainer = usesContainer || migration.usesContainer
+		if migration.starts && (declaringFile == nil || !migrations[declaringFile].starts) {
+			declaringFile = file
+		}
+		if migration.usesContainer && declaringFile == nil {
+			declaringFile = file
+		}
+	}
+
+	if len(migrations) > 0 {
+		findings = append(findings, injectorFindings(fset, files)...)
+	}
+
+	if declaringFile != nil {
+		migration := migrations[declaringFile]
+		migration.edits = append(migration.edits, edit{
+			start:       declarationOffset(fset, declaringFile.syntax),
+			end:         declarationOffset(fset, declaringFile.syntax),
+			replacement: fmt.Sprintf("\n\nvar %s = %s.NewContainer()\n", container, migration.target),
+		})
+		if usesContainer && !registers {
+			findings = append(findings, finding{
+				Position: fset.Position(declaringFile.syntax.Name.Pos()),
+				Message:  fmt.Sprintf("package %s resolves services but registers none; %s starts empty, pass in the container that holds the services instead", declaringFile.syntax.Name.Name, container),
+			})
+		}
+	}
+
+	var migrated []migratedFile
+	for _, file := range files {
+		migration, found := migrations[file]
+		if !found || len(migration.edits) == 0 {
+			continue
+		}
+		source, err := format.Source(applyEdits(file.source, migration.edits))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: formatting migrated source: %w", file.path, err)
+		}
+		migrated = append(migrated, migratedFile{Path: file.path, Source: source})
+	}
+	return migrated, findings, nil
+}
+
+// injectorFindings reports the implementations of v0's IInjector. Register also registered
+// what InjectorInsertion returns, which v1 Inject does not know about.
+func injectorFindings(fset *token.FileSet, files []*sourceFile) []finding {
+	var findings []finding
+	for _, file := range files {
+		for _, declaration := range file.syntax.Decls {
+			method, ok := declaration.(*ast.FuncDecl)
+			if !ok || method.Recv == nil || method.Name.Name != "InjectorInsertion" || method.Type.Params.NumFields() != 0 {
+				continue
+			}
+			receiver := method.Recv.List[0].Type
+			if star, ok := receiver.(*ast.StarExpr); ok {
+				receiver = star.X
+			}
+			findings = append(findings, finding{
+				Position: fset.Position(method.Name.Pos()),
+				Message:  fmt.Sprintf("%s implements IInjector: v1 Inject does not register what InjectorInsertion returns; rename it to ProvideService to implement sioc.ServiceProvider", file.source[fset.Position(receiver.Pos()).Offset:fset.Position(receiver.End()).Offset]),
+			})
+		}
+	}
+	return findings
+}
+
+// lookupKind tells how v1 resolves the type argument of a v0 Get call.
+type lookupKind int
+
+const (
+	// directLookup types, pointers and interfaces, resolve in v1 as they did in v0.
+	directLookup lookupKind = iota
+	// valueLookup types were resolved by v0 from their registered pointer, which v1 does not do.
+	valueLookup
+	// unknownLookup types are declared elsewhere, so their kind cannot be told from the source.
+	unknownLookup
+)
+
+// classifyLookup tells the kind of a Get type argument, following the types declared in the package.
+func classifyLookup(expression ast.Expr, declaredTypes map[string]ast.Expr) lookupKind {
+	switch expression := expression.(type) {
+	case *ast.StarExpr, *ast.InterfaceType:
+		return directLookup
+	case *ast.ArrayType, *ast.MapType, *ast.StructType, *ast.FuncType, *ast.ChanType:
+		return valueLookup
+	case *ast.ParenExpr:
+		return classifyLookup(expression.X, declaredTypes)
+	case *ast.Ident:
+		if declared, found := declaredTypes[expression.Name]; found {
+			return classifyLookup(declared, declaredTypes)
+		}
+		if predeclared, ok := types.Universe.Lookup(expression.Name).(*types.TypeName); ok {
+			if types.IsInterface(predeclared.Type()) {
+				return directLookup
+			}
+			return valueLookup
+		}
+	}
+	return unknownLookup
+}
+
+// postfixOperand returns the call a selector, index, slice, type assertion or call applies to.
+// Dereferencing such a call needs parentheses.
+func postfixOperand(node ast.Node) *ast.CallExpr {
+	var operand ast.Expr
+	switch node := node.(type) {
+	case *ast.SelectorExpr:
+		operand = node.X
+	case *ast.IndexExpr:
+		operand = node.X
+	case *ast.SliceExpr:
+		operand = node.X
+	case *ast.TypeAssertExpr:
+		operand = node.X
+	case *ast.CallExpr:
+		operand = node.Fun
+	}
+	call, _ := operand.(*ast.CallExpr)
+	return call
+}
+
+// migrateFile computes the edits that move one file from the v0 global API to v1.
+func migrateFile(fset *token.FileSet, file *ast.File, source []byte, container string, declaredTypes map[string]ast.Expr) *fileMigration {
+	migration := &fileMigration{}
+	offset := func(position token.Pos) int {
+		return fset.Position(position).Offset
+	}
+	report := func(position token.Pos, format string, arguments ...any) {
+		migration.findings = append(migration.findings, finding{Position: fset.Position(position), Message: fmt.Sprintf(format, arguments...)})
+	}
+	reportError := func(position token.Pos, format string, arguments ...any) {
+		migration.findings = append(migration.findings, finding{Position: fset.Position(position), Message: fmt.Sprintf(format, arguments...), Error: true})
+	}
+	text := func(node ast.Node) string {
+		return string(source[offset(node.Pos()):offset(node.End())])
+	}
+
+	// The first legacy import is turned into the v1 import, unless v1 is already imported.
+	legacyNames := make(map[string]bool)
+	var legacyImports []*ast.ImportSpec
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := "sioc"
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		switch path {
+		case v1Path:
+			migration.target = name
+		case v0Path, rootPath:
+			if name == "." || name == "_" {
+				report(spec.Pos(), "cannot convert %s import of %s", name, path)
+				continue
+			}
+			legacyNames[name] = true
+			legacyImports = append(legacyImports, spec)
+		}
+	}
+	if len(legacyImports) == 0 {
+		return migration
+	}
+	for index, spec := range legacyImports {
+		if index == 0 && migration.target == "" {
+			migration.target = importName(spec)
+			migration.edits = append(migration.edits, edit{offset(spec.Path.Pos()), offset(spec.Path.End()), strconv.Quote(v1Path)})
+			continue
+		}
+		migration.edits = append(migration.edits, removeImport(fset, file, source, spec))
+	}
+
+	// legacySelector returns the selector when the expression is a use of a legacy package.
+	legacySelector := func(expression ast.Expr) *ast.SelectorExpr {
+		selector, ok := expression.(*ast.SelectorExpr)
+		if !ok {
+			return nil
+		}
+		identifier, ok := selector.X.(*ast.Ident)
+		if !ok || identifier.Obj != nil || !legacyNames[identifier.Name] {
+			return nil
+		}
+		return selector
+	}
+	renamePackage := func(selector *ast.SelectorExpr) {
+		if identifier := selector.X.(*ast.Ident); identifier.Name != migration.target {
+			migration.edits = append(migration.edits, edit{offset(identifier.Pos()), offset(identifier.End()), migration.target})
+		}
+	}
+	appendContainer := func(call *ast.CallExpr) {
+		migration.usesContainer = true
+		if len(call.Args) == 0 {
+			migration.edits = append(migration.edits, edit{offset(call.Lparen) + 1, offset(call.Lparen) + 1, container})
+			return
+		}
+		lastArgument := offset(call.Args[len(call.Args)-1].End())
+		migration.edits = append(migration.edits, edit{lastArgument, lastArgument, ", " + container})
+	}
+
+	// v0 resolved Get[T] of a value type from the registered *T; v1 needs the pointer type.
+	postfix := make(map[*ast.CallExpr]bool)
+	dereferenceLookup := func(call *ast.CallExpr) {
+		instance, ok := call.Fun.(*ast.IndexExpr)
+		if !ok {
+			return
+		}
+		switch classifyLookup(instance.Index, declaredTypes) {
+		case valueLookup:
+			prefix := "*"
+			if postfix[call] {
+				prefix = "(*"
+				migration.edits = append(migration.edits, edit{offset(call.End()), offset(call.End()), ")"})
+			}
+			migration.edits = append(migration.edits,
+				edit{offset(call.Pos()), offset(call.Pos()), prefix},
+				edit{offset(instance.Index.Pos()), offset(instance.Index.Pos()), "*"})
+		case unknownLookup:
+			report(call.Pos(), "cannot tell whether %[1]s is a pointer or an interface: v1 does not resolve a value type from its registered pointer, use *%[2]s.Get[*%[1]s](%[3]s) if it is neither", text(instance.Index), migration.target, container)
+		}
+	}
+
+	handled := make(map[*ast.SelectorExpr]bool)
+	ast.Inspect(file, func(node ast.Node) bool {
+		if call := postfixOperand(node); call != nil {
+			postfix[call] = true
+		}
+		switch node := node.(type) {
+		case *ast.ExprStmt:
+			// The container is declared at package level, so Start calls are dropped.
+			call, ok := node.X.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if selector := legacySelector(call.Fun); selector != nil && selector.Sel.Name == "Start" {
+				migration.starts = true
+				migration.edits = append(migration.edits, removeLine(fset, file, source, node.Pos(), node.End()))
+				return false
+			}
+		case *ast.CallExpr:
+			selector := legacySelector(genericFunction(node.Fun))
+			if selector == nil {
+				return true
+			}
+			handled[selector] = true
+			switch selector.Sel.Name {
+			case "Register":
+				migration.registers = true
+				migration.edits = append(migration.edits, edit{offset(node.Fun.Pos()), offset(node.Fun.End()), migration.target + ".Inject"})
+				appendContainer(node)
+			case "Get":
+				// The dereference is inserted where the package name starts, so it goes first.
+				dereferenceLookup(node)
+				renamePackage(selector)
+				appendContainer(node)
+			case "Init":
+				renamePackage(selector)
+				appendContainer(node)
+			case "Len":
+				migration.usesContainer = true
+				migration.edits = append(migration.edits, edit{offset(node.Pos()), offset(node.End()), container + ".Count()"})
+				return false
+			case "GetFunctionName":
+				renamePackage(selector)
+			case "ClearList":
+				reportError(node.Pos(), "cannot convert %s.ClearList: v1 containers cannot be cleared, create a new container instead", selector.X)
+			case "SetLogHandler":
+				reportError(node.Pos(), "cannot convert %s.SetLogHandler: pass sioc.WithLogHandler to NewContainer instead", selector.X)
+			default:
+				reportError(node.Pos(), "cannot convert %s.%s: no v1 equivalent", selector.X, selector.Sel.Name)
+			}
+		case *ast.SelectorExpr:
+			selector := legacySelector(node)
+			if selector == nil || handled[selector] {
+				return true
+			}
+			switch selector.Sel.Name {
+			case "InitializeNewInstanceTo":
+				migration.edits = append(migration.edits, edit{offset(selector.Pos()), offset(selector.End()), migration.target + ".InstanceCreationMode"})
+			case "NEW":
+				migration.edits = append(migration.edits, edit{offset(selector.Pos()), offset(selector.End()), migration.target + ".CreateNewInstance"})
+			case "GetFunctionName":
+				renamePackage(selector)
+			case "Start", "Register", "Get", "Init", "Len":
+				report(selector.Pos(), "cannot convert %s.%s used as a value: the v1 function takes the container as an argument", selector.X, selector.Sel.Name)
+			default:
+				reportError(selector.Pos(), "cannot convert %s.%s: no v1 equivalent", selector.X, selector.Sel.Name)
+			}
+		}
+		return true
+	})
+	return migration
+}
+
+// genericFunction strips the type arguments of an instantiated generic function.
+func genericFunction(function ast.Expr) ast.Expr {
+	switch function := function.(type) {
+	case *ast.IndexExpr:
+		return function.X
+	case *ast.IndexListExpr:
+		return function.X
+	case *ast.ParenExpr:
+		return genericFunction(function.X)
+	}
+	return function
+}
+
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	return "sioc"
+}
+
+func importsLegacy(file *ast.File) bool {
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		if path == v0Path || path == rootPath {
+			return true
+		}
+	}
+	return false
+}
+
+// findDeclaration returns the first declaration of the name in the file, at package level or
+// in a function. Struct fields are ignored, since they cannot shadow a package-level variable.
+func findDeclaration(file *ast.File, name string) (token.Pos, bool) {
+	fields := make(map[*ast.Ident]bool)
+	position := token.NoPos
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.StructType:
+			for _, field := range node.Fields.List {
+				for _, fieldName := range field.Names {
+					fields[fieldName] = true
+				}
+			}
+		case *ast.Ident:
+			if node.Name == name && node.Obj != nil && !fields[node] {
+				position = node.Pos()
+			}
+		}
+		return position == token.NoPos
+	})
+	return position, position != token.NoPos
+}
+
+// removeImport deletes an import spec, or the whole declaration when it is the only spec.
+func removeImport(fset *token.FileSet, file *ast.File, source []byte, spec *ast.ImportSpec) edit {
+	for _, declaration := range file.Decls {
+		if genDecl, ok := declaration.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT && len(genDecl.Specs) == 1 && genDecl.Specs[0] == spec {
+			return removeLine(fset, file, source, genDecl.Pos(), genDecl.End())
+		}
+	}
+	return removeLine(fset, file, source, spec.Pos(), spec.End())
+}
+
+// removeLine deletes the node, together with its line when nothing else is on it.
+func removeLine(fset *token.FileSet, file *ast.File, source []byte, start, end token.Pos) edit {
+	tokenFile := fset.File(file.Pos())
+	startPosition, endPosition := fset.Position(start), fset.Position(end)
+	lineStart := tokenFile.Offset(tokenFile.LineStart(startPosition.Line))
+	nextLine := tokenFile.Size()
+	if endPosition.Line < tokenFile.LineCount() {
+		nextLine = tokenFile.Offset(tokenFile.LineStart(endPosition.Line + 1))
+	}
+	before := source[lineStart:startPosition.Offset]
+	after := source[endPosition.Offset:nextLine]
+	if len(bytes.TrimSpace(before)) > 0 || len(bytes.TrimSpace(after)) > 0 {
+		return edit{start: startPosition.Offset, end: endPosition.Offset}
+	}
+	return edit{start: lineStart, end: nextLine}
+}
+
+// declarationOffset returns where the container declaration goes: after the imports.
+func declarationOffset(fset *token.FileSet, file *ast.File) int {
+	position := file.Name.End()
+	for _, declaration := range file.Decls {
+		if genDecl, ok := declaration.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			position = genDecl.End()
+		}
+	}
+	return fset.Position(position).Offset
+}
+
+// applyEdits applies non-overlapping edits to the source.
+func applyEdits(source []byte, edits []edit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+	var result bytes.Buffer
+	position := 0
+	for _, e := range edits {
+		if e.start < position {
+			continue
+		}
+		result.Write(source[position:e.start])
+		result.WriteString(e.replacement)
+		position = e.end
+	}
+	result.Write(source[position:])
+	return result.Bytes()
+}
+
+// goFilesByDirectory collects the Go files in the paths, grouped by directory.
+// Like the go command, it skips testdata, vendor and directories starting with "." or "_".
+func goFilesByDirectory(paths []string) (map[string][]string, error) {
+	directories := make(map[string][]string)
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			name := entry.Name()
+			if entry.IsDir() {
+				if path != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(name, ".go") {
+				directories[filepath.Dir(path)] = append(directories[filepath.Dir(path)], path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return directories, nil
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func migrateTestdata(t *testing.T, name string, container string) ([]migratedFile, []finding) {
+	t.Helper()
+	migrated, findings, err := migrate(migrateConfig{Paths: []string{filepath.Join("testdata", name)}, Container: container})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return migrated, findings
+}
+
+// TestMigrateRewritesLegacyCalls tests the rewritten files against their golden versions
+func TestMigrateRewritesLegacyCalls(t *testing.T) {
+	migrated, findings := migrateTestdata(t, "legacy", "container")
+
+	if len(migrated) != 2 {
+		t.Fatalf("Expected 2 migrated files, got %d", len(migrated))
+	}
+	for _, file := range migrated {
+		golden, err := os.ReadFile(file.Path + ".golden")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(file.Source) != string(golden) {
+			t.Errorf("Unexpected migration of %s:\n%s", file.Path, file.Source)
+		}
+	}
+
+	if len(findings) != 1 || !strings.Contains(findings[0].Message, "ClearList") {
+		t.Fatalf("Expected a single ClearList finding, got %v", findings)
+	}
+	if findings[0].Position.Line != 21 {
+		t.Errorf("Expected the finding on line 21, got %v", findings[0].Position)
+	}
+	if !findings[0].Error || !strings.Contains(findings[0].String(), "error: ") {
+		t.Errorf("Expected the leftover ClearList call to be reported as an error, got %v", findings[0])
+	}
+}
+
+// TestMigrateDereferencesValueLookups tests that Get of value types resolves their pointer
+func TestMigrateDereferencesValueLookups(t *testing.T) {
+	migrated, findings := migrateTestdata(t, "values", "container")
+
+	if len(migrated) != 1 {
+		t.Fatalf("Expected 1 migrated file, got %d", len(migrated))
+	}
+	golden, err := os.ReadFile(migrated[0].Path + ".golden")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(migrated[0].Source) != string(golden) {
+		t.Errorf("Unexpected migration of %s:\n%s", migrated[0].Path, migrated[0].Source)
+	}
+
+	var messages []string
+	for _, f := range findings {
+		if f.Error {
+			t.Errorf("Expected only warnings, got %v", f)
+		}
+		messages = append(messages, f.Message)
+	}
+	report := strings.Join(messages, "\n")
+	if len(findings) != 2 || !strings.Contains(report, "cannot tell whether T is a pointer") || !strings.Contains(report, "Plugin implements IInjector") {
+		t.Errorf("Expected findings for the generic lookup and the IInjector implementation, got %v", findings)
+	}
+}
+
+// TestMigrateReportsPackagesWithoutRegistrations tests that lookups into another package's services are reported
+func TestMigrateReportsPackagesWithoutRegistrations(t *testing.T) {
+	migrated, findings := migrateTestdata(t, "lookups", "container")
+
+	if len(migrated) != 1 || !strings.Contains(string(migrated[0].Source), "sioc.Get[*Handler](container)") {
+		t.Fatalf("Expected the lookup to be migrated, got %v", migrated)
+	}
+	if len(findings) != 1 || !strings.Contains(findings[0].Message, "registers none") {
+		t.Errorf("Expected a finding about missing registrations, got %v", findings)
+	}
+}
+
+// TestMigrateContainerNameCollision tests that packages already declaring the container name are skipped
+func TestMigrateContainerNameCollision(t *testing.T) {
+	migrated, findings := migrateTestdata(t, "collision", "container")
+	if len(migrated) != 0 {
+		t.Errorf("Expected no migrated files, got %d", len(migrated))
+	}
+	if len(findings) != 1 || !strings.Contains(findings[0].Message, "-container") {
+		t.Errorf("Expected a name collision finding, got %v", findings)
+	}
+
+	migrated, findings = migrateTestdata(t, "collision", "services")
+	if len(findings) != 0 {
+		t.Errorf("Expected no findings, got %v", findings)
+	}
+	if len(migrated) != 1 || !strings.Contains(string(migrated[0].Source), "sioc.Inject(service, services)") {
+		t.Errorf("Expected the container to be named services, got %v", migrated)
+	}
+}
+
+// TestMigrateRemovesLegacyImports tests that migrated files only import sioc/v1
+func TestMigrateRemovesLegacyImports(t *testing.T) {
+	migrated, _ := migrateTestdata(t, "legacy", "container")
+	for _, file := range migrated {
+		if strings.Contains(string(file.Source), "github.com/sergiodii/sioc/v0") || strings.Contains(string(file.Source), "\"github.com/sergiodii/sioc\"") {
+			t.Errorf("Expected %s to no longer import the v0 API", file.Path)
+		}
+	}
+}
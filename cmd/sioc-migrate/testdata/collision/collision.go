@@ -0,0 +1,12 @@
+package collision
+
+import sioc "github.com/sergiodii/sioc/v0"
+
+type Service struct{}
+
+func Run() {
+	container := []any{&Service{}}
+	for _, service := range container {
+		sioc.Register(service)
+	}
+}
@@ -0,0 +1,22 @@
+package legacy
+
+import (
+	"fmt"
+
+	sioc "github.com/sergiodii/sioc/v0"
+)
+
+func Run() {
+	// Services must be registered before Init.
+	sioc.Start()
+	sioc.Register(&Repository{})
+	sioc.Register(
+		&UserService{},
+	)
+	sioc.Init()
+
+	users := sioc.Get[*UserService]()
+	fmt.Println(users.Name(), sioc.Len())
+
+	sioc.ClearList()
+}
@@ -0,0 +1,29 @@
+package legacy
+
+import (
+	"github.com/sergiodii/sioc"
+	v1 "github.com/sergiodii/sioc/v1"
+)
+
+type Repository struct{}
+
+type UserService struct {
+	repository *Repository
+	mode       sioc.InitializeNewInstanceTo
+}
+
+func (u *UserService) Init(mode sioc.InitializeNewInstanceTo, repository *Repository) {
+	u.mode = mode
+	u.repository = repository
+}
+
+func (u *UserService) Name() string {
+	if u.mode == sioc.NEW {
+		return "users"
+	}
+	return v1.GetFunctionName(u.Name)
+}
+
+func register() {
+	sioc.Register[*Repository](&Repository{})
+}
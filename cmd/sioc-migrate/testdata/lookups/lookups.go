@@ -0,0 +1,9 @@
+package lookups
+
+import sioc "github.com/sergiodii/sioc/v0"
+
+type Handler struct{}
+
+func Handle() *Handler {
+	return sioc.Get[*Handler]()
+}
@@ -0,0 +1,40 @@
+package values
+
+import (
+	"fmt"
+
+	sioc "github.com/sergiodii/sioc/v0"
+)
+
+type Settings struct {
+	Name string
+}
+
+type Logger interface {
+	Log(message string)
+}
+
+type Names []string
+
+type Defaults = Settings
+
+// Plugin registers its settings along with itself.
+type Plugin struct{}
+
+func (p *Plugin) InjectorInsertion() any {
+	return &Settings{Name: "plugin"}
+}
+
+func Run[T any]() {
+	sioc.Register(&Plugin{})
+	sioc.Init()
+
+	settings := sioc.Get[Settings]()
+	defaults := sioc.Get[Defaults]()
+	logger := sioc.Get[Logger]()
+	names := sioc.Get[Names]()
+	retries := sioc.Get[int]()
+	pointer := sioc.Get[*Settings]()
+	generic := sioc.Get[T]()
+	fmt.Println(settings, defaults, logger, names, retries, pointer, generic, sioc.Get[Settings]().Name)
+}
@@ -0,0 +1,17 @@
+// Command siocvet reports common misuses of sioc.
+//
+// It runs standalone or through go vet:
+//
+//	siocvet ./...
+//	go vet -vettool=$(which siocvet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/sergiodii/sioc/analysis/siocvet"
+)
+
+func main() {
+	singlechecker.Main(siocvet.Analyzer)
+}
@@ -0,0 +1,261 @@
+// Package clone makes deep copies of service instances, so that "new instance" injection
+// does not share pointers with the registered singleton.
+package clone
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// ErrNotCloneable is returned for values that cannot be deep copied, such as channels.
+var ErrNotCloneable = errors.New("not cloneable")
+
+// Cloner is implemented by values that copy themselves. Deep uses Clone instead of copying
+// the value field by field; Clone must not call Deep on its own receiver.
+type Cloner interface {
+	Clone() any
+}
+
+// InstanceFactory is implemented by values that build new instances of themselves. It is the
+// v0 IInstanceFactory, and Deep treats it like Cloner, preferring Clone when a value has both.
+type InstanceFactory interface {
+	NewInstance() any
+}
+
+var (
+	clonerType          = reflect.TypeOf((*Cloner)(nil)).Elem()
+	instanceFactoryType = reflect.TypeOf((*InstanceFactory)(nil)).Elem()
+)
+
+// resetTypes are synchronization primitives that start at their zero value in a copy, so a
+// new instance never inherits a held lock or a pending wait.
+var resetTypes = map[reflect.Type]bool{
+	reflect.TypeOf(sync.Mutex{}):     true,
+	reflect.TypeOf(sync.RWMutex{}):   true,
+	reflect.TypeOf(sync.WaitGroup{}): true,
+	reflect.TypeOf(sync.Once{}):      true,
+}
+
+// rejectedTypes hold state that a zero value would silently drop.
+var rejectedTypes = map[reflect.Type]bool{
+	reflect.TypeOf(sync.Cond{}): true,
+	reflect.TypeOf(sync.Map{}):  true,
+	reflect.TypeOf(sync.Pool{}): true,
+}
+
+// Deep returns a deep copy of the value. Pointers, structs (including unexported fields),
+// slices, arrays, maps and interfaces are copied recursively, and values reachable through
+// several pointers, including cycles, are copied once. Values implementing Cloner or
+// InstanceFactory copy themselves. Functions are shared, sync.Mutex, RWMutex, WaitGroup and Once start unlocked
+// and empty, and channels, sync.Cond, sync.Map and sync.Pool are rejected with ErrNotCloneable.
+func Deep(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	copier := &copier{copies: make(map[visit]reflect.Value)}
+	copied, err := copier.copy(reflect.ValueOf(value))
+	if err != nil {
+		return nil, err
+	}
+	return copied.Interface(), nil
+}
+
+// visit identifies a pointer, slice or map that was already copied.
+type visit struct {
+	pointer uintptr
+	typ     reflect.Type
+}
+
+type copier struct {
+	copies map[visit]reflect.Value
+}
+
+func (c *copier) copy(original reflect.Value) (reflect.Value, error) {
+	originalType := original.Type()
+	if (originalType.Implements(clonerType) || originalType.Implements(instanceFactoryType)) && !isNil(original) {
+		return c.cloneWith(original)
+	}
+	if resetTypes[originalType] {
+		return reflect.Zero(originalType), nil
+	}
+	if rejectedTypes[originalType] {
+		return reflect.Value{}, fmt.Errorf("%w: %v", ErrNotCloneable, originalType)
+	}
+
+	switch original.Kind() {
+	case reflect.Pointer:
+		if original.IsNil() {
+			return reflect.Zero(original.Type()), nil
+		}
+		key := visit{original.Pointer(), original.Type()}
+		if copied, found := c.copies[key]; found {
+			return copied, nil
+		}
+		copied := reflect.New(original.Type().Elem())
+		c.copies[key] = copied
+		element, err := c.copy(original.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		settable(copied.Elem()).Set(element)
+		return copied, nil
+
+	case reflect.Struct:
+		original = addressable(original)
+		copied := reflect.New(original.Type()).Elem()
+		for index := 0; index < original.NumField(); index++ {
+			field, err := c.copy(readable(original.Field(index)))
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("%v.%s: %w", original.Type(), original.Type().Field(index).Name, err)
+			}
+			settable(copied.Field(index)).Set(field)
+		}
+		return copied, nil
+
+	case reflect.Slice:
+		if original.IsNil() {
+			return reflect.Zero(original.Type()), nil
+		}
+		key := visit{original.Pointer(), original.Type()}
+		if copied, found := c.copies[key]; found && copied.Len() == original.Len() {
+			return copied, nil
+		}
+		copied := reflect.MakeSlice(original.Type(), original.Len(), original.Cap())
+		c.copies[key] = copied
+		for index := 0; index < original.Len(); index++ {
+			element, err := c.copy(readable(original.Index(index)))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			copied.Index(index).Set(element)
+		}
+		return copied, nil
+
+	case reflect.Array:
+		original = addressable(original)
+		copied := reflect.New(original.Type()).Elem()
+		for index := 0; index < original.Len(); index++ {
+			element, err := c.copy(readable(original.Index(index)))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			copied.Index(index).Set(element)
+		}
+		return copied, nil
+
+	case reflect.Map:
+		if original.IsNil() {
+			return reflect.Zero(original.Type()), nil
+		}
+		key := visit{original.Pointer(), original.Type()}
+		if copied, found := c.copies[key]; found {
+			return copied, nil
+		}
+		copied := reflect.MakeMapWithSize(original.Type(), original.Len())
+		c.copies[key] = copied
+		iterator := original.MapRange()
+		for iterator.Next() {
+			mapKey, err := c.copy(readable(iterator.Key()))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			mapValue, err := c.copy(readable(iterator.Value()))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			copied.SetMapIndex(mapKey, mapValue)
+		}
+		return copied, nil
+
+	case reflect.Interface:
+		if original.IsNil() {
+			return reflect.Zero(original.Type()), nil
+		}
+		element, err := c.copy(readable(original.Elem()))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		copied := reflect.New(original.Type()).Elem()
+		copied.Set(element)
+		return copied, nil
+
+	case reflect.Chan, reflect.UnsafePointer:
+		return reflect.Value{}, fmt.Errorf("%w: %v", ErrNotCloneable, original.Type())
+	}
+
+	// Basic kinds are copied by value; functions are shared.
+	copied := reflect.New(original.Type()).Elem()
+	copied.Set(readable(original))
+	return copied, nil
+}
+
+// cloneWith copies the value with its Clone method.
+func (c *copier) cloneWith(original reflect.Value) (reflect.Value, error) {
+	if original.Kind() == reflect.Pointer {
+		key := visit{original.Pointer(), original.Type()}
+		if copied, found := c.copies[key]; found {
+			return copied, nil
+		}
+		copied, err := cloneValue(original)
+		if err == nil {
+			c.copies[key] = copied
+		}
+		return copied, err
+	}
+	return cloneValue(original)
+}
+
+func cloneValue(original reflect.Value) (reflect.Value, error) {
+	var cloned any
+	method := "Clone"
+	switch self := readable(original).Interface().(type) {
+	case Cloner:
+		cloned = self.Clone()
+	case InstanceFactory:
+		cloned, method = self.NewInstance(), "NewInstance"
+	}
+	clonedValue := reflect.ValueOf(cloned)
+	if cloned == nil || !clonedValue.Type().AssignableTo(original.Type()) {
+		return reflect.Value{}, fmt.Errorf("%v.%s returned %T", original.Type(), method, cloned)
+	}
+	copied := reflect.New(original.Type()).Elem()
+	copied.Set(clonedValue)
+	return copied, nil
+}
+
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func:
+		return value.IsNil()
+	}
+	return false
+}
+
+// addressable returns an addressable copy of the value, so its unexported fields can be read.
+func addressable(value reflect.Value) reflect.Value {
+	if value.CanAddr() {
+		return value
+	}
+	copied := reflect.New(value.Type()).Elem()
+	copied.Set(value)
+	return copied
+}
+
+// readable returns the value with the read-only flag of unexported fields cleared.
+func readable(value reflect.Value) reflect.Value {
+	if value.CanInterface() || !value.CanAddr() {
+		return value
+	}
+	return reflect.NewAt(value.Type(), unsafe.Pointer(value.UnsafeAddr())).Elem()
+}
+
+// settable returns an addressable value that can be set, even for unexported fields.
+func settable(value reflect.Value) reflect.Value {
+	if value.CanSet() {
+		return value
+	}
+	return reflect.NewAt(value.Type(), unsafe.Pointer(value.UnsafeAddr())).Elem()
+}
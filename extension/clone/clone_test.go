@@ -0,0 +1,164 @@
+package clone
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type node struct {
+	Name     string
+	Next     *node
+	children []*node
+	labels   map[string][]string
+	data     [2]*int
+	value    any
+}
+
+func TestDeepCopiesNestedValues(t *testing.T) {
+	number := 7
+	original := &node{
+		Name:   "root",
+		labels: map[string][]string{"team": {"a", "b"}},
+		data:   [2]*int{&number, nil},
+		value:  &node{Name: "boxed"},
+	}
+	original.children = []*node{{Name: "child"}}
+
+	copied, err := Deep(original)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := copied.(*node)
+
+	if result == original || result.children[0] == original.children[0] || result.data[0] == original.data[0] {
+		t.Fatal("Expected no pointers to be shared with the original")
+	}
+	if result.value.(*node) == original.value.(*node) || result.value.(*node).Name != "boxed" {
+		t.Errorf("Expected the interface value to be copied, got %+v", result.value)
+	}
+
+	result.labels["team"][0] = "changed"
+	*result.data[0] = 8
+	result.children[0].Name = "changed"
+	if original.labels["team"][0] != "a" || number != 7 || original.children[0].Name != "child" {
+		t.Errorf("Expected the original to be unchanged, got %+v", original)
+	}
+}
+
+func TestDeepPreservesSharingAndCycles(t *testing.T) {
+	first := &node{Name: "first"}
+	second := &node{Name: "second", Next: first}
+	first.Next = second
+	first.children = []*node{second, second}
+
+	copied, err := Deep(first)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := copied.(*node)
+
+	if result.Next.Next != result {
+		t.Error("Expected the cycle to be preserved in the copy")
+	}
+	if result.children[0] != result.Next || result.children[1] != result.Next {
+		t.Error("Expected values reachable twice to be copied once")
+	}
+	if result.Next == second {
+		t.Error("Expected the cycle members to be copied")
+	}
+}
+
+func TestDeepNil(t *testing.T) {
+	copied, err := Deep(nil)
+	if copied != nil || err != nil {
+		t.Errorf("Expected nil, got %v, %v", copied, err)
+	}
+}
+
+type lockedCounter struct {
+	mutex sync.Mutex
+	count int
+}
+
+type withChannel struct {
+	events chan int
+}
+
+type selfCloning struct {
+	Name   string
+	clones *int
+}
+
+func (sc *selfCloning) Clone() any {
+	*sc.clones++
+	return &selfCloning{Name: sc.Name + " clone", clones: sc.clones}
+}
+
+func TestDeepResetsLocks(t *testing.T) {
+	original := &lockedCounter{count: 3}
+	original.mutex.Lock()
+	defer original.mutex.Unlock()
+
+	copied, err := Deep(original)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := copied.(*lockedCounter)
+	if !result.mutex.TryLock() {
+		t.Error("Expected the copied mutex to start unlocked")
+	}
+	if result.count != 3 {
+		t.Errorf("Expected the count to be copied, got %d", result.count)
+	}
+}
+
+func TestDeepRejectsChannels(t *testing.T) {
+	_, err := Deep(&withChannel{events: make(chan int)})
+	if !errors.Is(err, ErrNotCloneable) {
+		t.Fatalf("Expected ErrNotCloneable, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "withChannel.events") {
+		t.Errorf("Expected the error to name the field, got %v", err)
+	}
+
+	if _, err := Deep(&struct{ Values sync.Map }{}); !errors.Is(err, ErrNotCloneable) {
+		t.Errorf("Expected ErrNotCloneable for sync.Map, got %v", err)
+	}
+}
+
+type selfBuilding struct {
+	Name string
+}
+
+func (sb *selfBuilding) NewInstance() any {
+	return &selfBuilding{Name: sb.Name + " instance"}
+}
+
+func TestDeepUsesInstanceFactory(t *testing.T) {
+	copied, err := Deep(&selfBuilding{Name: "service"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result := copied.(*selfBuilding); result.Name != "service instance" {
+		t.Errorf("Expected the instance built by NewInstance, got %+v", result)
+	}
+}
+
+func TestDeepUsesCloner(t *testing.T) {
+	clones := 0
+	shared := &selfCloning{Name: "service", clones: &clones}
+
+	copied, err := Deep([]*selfCloning{shared, shared})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := copied.([]*selfCloning)
+	if result[0].Name != "service clone" || result[0] != result[1] {
+		t.Errorf("Expected a single clone built by Clone, got %+v", result)
+	}
+	if clones != 1 {
+		t.Errorf("Expected Clone to be called once, got %d", clones)
+	}
+}
@@ -0,0 +1,17 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Discard is a slog.Handler that drops every record. It is the default for
+// containers that were not given a handler, so the library stays silent.
+var Discard slog.Handler = discardHandler{}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
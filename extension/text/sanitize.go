@@ -2,6 +2,9 @@ package text
 
 import "strings"
 
+// Sanitize removes every "*" from the text.
+//
+// Deprecated: service keys are no longer sanitized; sioc builds them with typekey.Of.
 func Sanitize(text string) string {
 	return strings.ReplaceAll(text, "*", "")
 }
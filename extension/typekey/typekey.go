@@ -0,0 +1,107 @@
+// Package typekey builds canonical service keys from reflect types.
+//
+// A key spells out the full package path of every named type, one "*" per pointer level and
+// the type arguments of generic instantiations, so distinct types never share a key:
+//
+//	*github.com/acme/shop/repo.Repo[github.com/acme/shop/model.User]
+//
+// Readable turns a key back into the short form used in messages: *repo.Repo[model.User].
+package typekey
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Of returns the canonical key of the type.
+func Of(t reflect.Type) string {
+	var builder strings.Builder
+	write(&builder, t)
+	return builder.String()
+}
+
+func write(builder *strings.Builder, t reflect.Type) {
+	if t == nil {
+		builder.WriteString("nil")
+		return
+	}
+	// The name of a generic instantiation already spells its type arguments with full paths.
+	if t.Name() != "" {
+		if t.PkgPath() != "" {
+			builder.WriteString(t.PkgPath())
+			builder.WriteByte('.')
+		}
+		builder.WriteString(t.Name())
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		builder.WriteByte('*')
+		write(builder, t.Elem())
+	case reflect.Slice:
+		builder.WriteString("[]")
+		write(builder, t.Elem())
+	case reflect.Array:
+		builder.WriteString("[" + strconv.Itoa(t.Len()) + "]")
+		write(builder, t.Elem())
+	case reflect.Map:
+		builder.WriteString("map[")
+		write(builder, t.Key())
+		builder.WriteByte(']')
+		write(builder, t.Elem())
+	case reflect.Chan:
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			builder.WriteString("<-chan ")
+		case reflect.SendDir:
+			builder.WriteString("chan<- ")
+		default:
+			builder.WriteString("chan ")
+		}
+		write(builder, t.Elem())
+	default:
+		// Function, struct and interface literals are keyed by their spelling.
+		builder.WriteString(t.String())
+	}
+}
+
+// Readable shortens every package path in the key to its last element.
+func Readable(key string) string {
+	var builder strings.Builder
+	for len(key) > 0 {
+		end := strings.IndexFunc(key, isDelimiter)
+		if end < 0 {
+			end = len(key)
+		}
+		if end == 0 {
+			builder.WriteByte(key[0])
+			key = key[1:]
+			continue
+		}
+		builder.WriteString(shorten(key[:end]))
+		key = key[end:]
+	}
+	return builder.String()
+}
+
+// shorten drops the directories of a qualified identifier: net/http.Request becomes http.Request.
+func shorten(identifier string) string {
+	dot := strings.LastIndexByte(identifier, '.')
+	if dot < 0 {
+		return identifier
+	}
+	if slash := strings.LastIndexByte(identifier[:dot], '/'); slash >= 0 {
+		return identifier[slash+1:]
+	}
+	return identifier
+}
+
+func isDelimiter(r rune) bool {
+	switch r {
+	case '*', '[', ']', ',', ' ', '(', ')', '{', '}', ';':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package typekey
+
+import (
+	htmltemplate "html/template"
+	"net/http"
+	"reflect"
+	"testing"
+	texttemplate "text/template"
+)
+
+type box[T any] struct{}
+
+func TestOf(t *testing.T) {
+	tests := []struct {
+		value any
+		key   string
+	}{
+		{"", "string"},
+		{&http.Request{}, "*net/http.Request"},
+		{new(*http.Request), "**net/http.Request"},
+		{box[*texttemplate.Template]{}, "github.com/sergiodii/sioc/extension/typekey.box[*text/template.Template]"},
+		{map[string][]*http.Client{}, "map[string][]*net/http.Client"},
+		{[2]int{}, "[2]int"},
+		{make(<-chan error), "<-chan error"},
+		{func(int) string { return "" }, "func(int) string"},
+	}
+	for _, test := range tests {
+		if key := Of(reflect.TypeOf(test.value)); key != test.key {
+			t.Errorf("Expected key %q for %T, got %q", test.key, test.value, key)
+		}
+	}
+}
+
+func TestOfDistinguishesPackagesAndPointers(t *testing.T) {
+	keys := map[string]bool{}
+	for _, value := range []any{
+		&texttemplate.Template{},
+		&htmltemplate.Template{},
+		texttemplate.Template{},
+		box[*texttemplate.Template]{},
+		box[*htmltemplate.Template]{},
+		box[texttemplate.Template]{},
+	} {
+		key := Of(reflect.TypeOf(value))
+		if keys[key] {
+			t.Errorf("Expected a distinct key for %T, got %q twice", value, key)
+		}
+		keys[key] = true
+	}
+}
+
+func TestReadable(t *testing.T) {
+	tests := map[string]string{
+		"string":            "string",
+		"*net/http.Request": "*http.Request",
+		"github.com/acme/repo.Repo[github.com/acme/model.User,*net/http.Client]": "repo.Repo[model.User,*http.Client]",
+		"map[string][]*net/http.Client":                                          "map[string][]*http.Client",
+		"func(*net/http.Request) error":                                          "func(*http.Request) error",
+	}
+	for key, readable := range tests {
+		if result := Readable(key); result != readable {
+			t.Errorf("Expected %q for %q, got %q", readable, key, result)
+		}
+	}
+}
+
+func TestReadableMatchesReflectForStandardLibrary(t *testing.T) {
+	for _, value := range []any{&http.Request{}, []*http.Client{}, map[string]http.Header{}} {
+		typ := reflect.TypeOf(value)
+		if readable := Readable(Of(typ)); readable != typ.String() {
+			t.Errorf("Expected %q, got %q", typ.String(), readable)
+		}
+	}
+}
@@ -1,21 +1,70 @@
 package sioc
 
 import (
-	"fmt"
 	"log"
+	"log/slog"
 	"reflect"
 	"runtime"
 	"strings"
 	"sync"
-	// v0_injection "github.com/sergiodii/sioc/v0"
+	"sync/atomic"
+	"time"
+
+	"github.com/sergiodii/sioc/extension/logging"
+	v1 "github.com/sergiodii/sioc/v1"
 )
 
 var injOnce sync.Once
+var logger atomic.Pointer[slog.Logger]
 var List *[]Injector[interface{}]
 var inj *injector[Injector[interface{}]]
 
+// initMutex serializes Init calls, which read and update the initialization state of the list.
+var initMutex sync.Mutex
+
+// backingContainer, when set by UseContainer, replaces inj.List as the storage of services.
+var backingContainer v1.ServiceContainer
+var containerMutex sync.RWMutex
+
+func init() {
+	logger.Store(slog.New(logging.Discard))
+}
+
 type injector[T any] struct {
-	List []*T
+	mutex sync.RWMutex
+	List  []*T
+}
+
+// items returns a copy of the list, safe to iterate while other goroutines register.
+func (i *injector[T]) items() []*T {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	return append([]*T(nil), i.List...)
+}
+
+func (i *injector[T]) add(items ...*T) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.List = append(i.List, items...)
+}
+
+func (i *injector[T]) len() int {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	return len(i.List)
+}
+
+func (i *injector[T]) clear() {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.List = []*T{}
+}
+
+// currentContainer returns the container set with UseContainer, if any.
+func currentContainer() v1.ServiceContainer {
+	containerMutex.RLock()
+	defer containerMutex.RUnlock()
+	return backingContainer
 }
 
 func Start() {
@@ -26,19 +75,33 @@ func Start() {
 
 func Get[T any]() T {
 	Start()
+	if serviceContainer := currentContainer(); serviceContainer != nil {
+		return getFromContainer[T](serviceContainer)
+	}
 	var element T
 	typeT := reflect.TypeOf((*T)(nil)).Elem()
 
-	for _, item := range inj.List {
+	for _, item := range inj.items() {
 		if instance := tryGetInstance[T](item, typeT); instance != nil {
 			return *instance
 		}
 	}
 
+	logger.Load().Error("service not found", slog.String("service", typeT.String()))
 	log.Fatalf("Instance type %s not found", reflect.TypeOf((*T)(nil)))
 	return element
 }
 
+// getFromContainer resolves T from a v1 container. Like the v0 list, it resolves a
+// non-pointer T from a registered *T.
+func getFromContainer[T any](serviceContainer v1.ServiceContainer) T {
+	switch reflect.TypeOf((*T)(nil)).Elem().Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v1.Get[T](serviceContainer)
+	}
+	return *v1.Get[*T](serviceContainer)
+}
+
 func tryGetInstance[T any](item *Injector[interface{}], typeT reflect.Type) *T {
 	if typeT.Kind() == reflect.Interface {
 		if reflect.TypeOf(item.GetInstance()).Implements(typeT) {
@@ -61,8 +124,29 @@ func tryGetInstance[T any](item *Injector[interface{}], typeT reflect.Type) *T {
 	return nil
 }
 
+// SetLogHandler sends registration and initialization events to the given slog.Handler.
+// Passing nil restores the default, which discards every event.
+func SetLogHandler(handler slog.Handler) {
+	if handler == nil {
+		handler = logging.Discard
+	}
+	logger.Store(slog.New(handler))
+}
+
+// UseContainer backs Register, Get, Init and Len with a v1 container, so v0 and v1 code
+// share the same singletons. Passing nil goes back to the package's own list.
+func UseContainer(serviceContainer v1.ServiceContainer) {
+	containerMutex.Lock()
+	defer containerMutex.Unlock()
+	backingContainer = serviceContainer
+}
+
 func Len() int {
-	return len(inj.List)
+	Start()
+	if serviceContainer := currentContainer(); serviceContainer != nil {
+		return serviceContainer.Count()
+	}
+	return inj.len()
 }
 
 // Inject injects a class into the dependency injection container.
@@ -70,21 +154,28 @@ func Len() int {
 func Register(cls interface{}) {
 	Start()
 
-	fmt.Println("InitializeNewInstanceTo", reflect.TypeOf(InitializeNewInstanceTo("")).String())
-
 	if reflect.TypeOf(cls).Kind() != reflect.Ptr {
+		logger.Load().Error("register failed: not a pointer", slog.String("service", reflect.TypeOf(cls).String()))
 		log.Fatalf("%s is not a pointer", reflect.TypeOf(cls).String())
 	}
 
-	injectInstance(cls)
-	injectFromIInjector(cls)
+	if serviceContainer := currentContainer(); serviceContainer != nil {
+		v1.Inject(cls, serviceContainer)
+		if injector, ok := cls.(IInjector); ok {
+			v1.Inject(injector.InjectorInsertion(), serviceContainer)
+		}
+	} else {
+		injectInstance(cls)
+		injectFromIInjector(cls)
+	}
+	logger.Load().Debug("service registered", slog.String("service", reflect.TypeOf(cls).String()))
 
 }
 
 func injectInstance(cls interface{}) {
 	newInj := NewInjector[interface{}]()
 	newInj.AddInstance(cls)
-	inj.List = append(inj.List, &newInj)
+	inj.add(&newInj)
 }
 
 func injectFromIInjector(cls interface{}) {
@@ -92,7 +183,7 @@ func injectFromIInjector(cls interface{}) {
 	if reflect.TypeOf(cls).Implements(iInjectorImplementation) {
 		newInj := NewInjector[interface{}]()
 		newInj.AddInstance(cls.(IInjector).InjectorInsertion())
-		inj.List = append(inj.List, &newInj)
+		inj.add(&newInj)
 	}
 }
 
@@ -110,27 +201,42 @@ type Status struct {
 
 func Init() {
 	Start()
-	dependencyMap := buildDependencyMap()
-	initializeInjectors(dependencyMap)
+	if serviceContainer := currentContainer(); serviceContainer != nil {
+		v1.Init(serviceContainer)
+		return
+	}
+	initMutex.Lock()
+	defer initMutex.Unlock()
+	startedAt := time.Now()
+	items := inj.items()
+	logger.Load().Info("init started", slog.Int("services", len(items)))
+	dependencyMap := buildDependencyMap(items)
+	initializeInjectors(items, dependencyMap)
+	logger.Load().Info("init finished", slog.Duration("duration", time.Since(startedAt)))
 }
 
-func buildDependencyMap() map[reflect.Type]*Injector[interface{}] {
+func buildDependencyMap(items []*Injector[interface{}]) map[reflect.Type]*Injector[interface{}] {
 	dependencyMap := make(map[reflect.Type]*Injector[interface{}])
-	for _, item := range inj.List {
+	for _, item := range items {
 		dependencyMap[reflect.TypeOf(item.GetInstance())] = item
 	}
 	return dependencyMap
 }
 
-func initializeInjectors(dependencyMap map[reflect.Type]*Injector[interface{}]) {
-	for _, item := range inj.List {
+func initializeInjectors(items []*Injector[interface{}], dependencyMap map[reflect.Type]*Injector[interface{}]) {
+	for _, item := range items {
 		initStart(item, dependencyMap)
-		fmt.Println("Injection: ", item.incjetionName, ", started")
 	}
 }
 
+// ClearList removes every service from the package's own list. Services in a container
+// set with UseContainer are kept, since v1 containers cannot be cleared.
 func ClearList() {
-	inj.List = []*Injector[interface{}]{}
+	Start()
+	if currentContainer() != nil {
+		logger.Load().Warn("ClearList has no effect on a v1 container")
+	}
+	inj.clear()
 }
 
 func initStart(inj *Injector[interface{}], m map[reflect.Type]*Injector[interface{}]) {
@@ -143,6 +249,9 @@ func initStart(inj *Injector[interface{}], m map[reflect.Type]*Injector[interfac
 		return
 	}
 
+	logger.Load().Debug("service init started", slog.String("service", inj.incjetionName))
+	startedAt := time.Now()
+
 	initType := init.Type()
 	if initType.NumIn() > 0 {
 		params := prepareInitParams(inj, initType, m)
@@ -152,6 +261,7 @@ func initStart(inj *Injector[interface{}], m map[reflect.Type]*Injector[interfac
 	}
 
 	inj.SetInitialization()
+	logger.Load().Debug("service init finished", slog.String("service", inj.incjetionName), slog.Duration("duration", time.Since(startedAt)))
 }
 
 func prepareInitParams(inj *Injector[interface{}], initType reflect.Type, m map[reflect.Type]*Injector[interface{}]) []reflect.Value {
@@ -174,12 +284,12 @@ func prepareInitParams(inj *Injector[interface{}], initType reflect.Type, m map[
 	return params
 }
 
+var preKeyType = reflect.TypeOf(InitializeNewInstanceTo(""))
+
 func handlePreKeyParam(paramType reflect.Type, i int, initType reflect.Type, params []reflect.Value) bool {
-	preKeyType := reflect.TypeOf(InitializeNewInstanceTo(""))
 	isPreKey := paramType == preKeyType
-	isNewInstance := (i != 0) && initType.In(i-1) == preKeyType
 
-	if isPreKey && !isNewInstance {
+	if isPreKey && !isNewInstance(i, initType) {
 		n := InitializeNewInstanceTo(NEW)
 		params[i] = reflect.ValueOf(n)
 		return true
@@ -187,8 +297,9 @@ func handlePreKeyParam(paramType reflect.Type, i int, initType reflect.Type, par
 	return false
 }
 
+// isNewInstance reports whether the parameter follows an InitializeNewInstanceTo marker.
 func isNewInstance(i int, initType reflect.Type) bool {
-	return (i != 0) && initType.In(i-1).String() == "offers_platform_core_injection.InitializeNewInstanceTo"
+	return (i != 0) && initType.In(i-1) == preKeyType
 }
 
 func findAndExecuteDependency(paramType reflect.Type, m map[reflect.Type]*Injector[interface{}], i int, initType reflect.Type, params []reflect.Value) {
@@ -197,6 +308,7 @@ func findAndExecuteDependency(paramType reflect.Type, m map[reflect.Type]*Inject
 			return
 		}
 	}
+	logger.Load().Error("dependency not found", slog.String("dependency", paramType.String()), slog.String("init", initType.String()))
 	log.Fatalf("Dependency not found for Init of %v: %v", reflect.TypeOf(i), paramType)
 }
 
@@ -1,8 +1,12 @@
 package sioc
 
 import (
+	"log"
+	"log/slog"
 	"reflect"
 	"strings"
+
+	"github.com/sergiodii/sioc/extension/clone"
 )
 
 type Injector[T any] struct {
@@ -29,10 +33,15 @@ func (i Injector[T]) GetInstance() T {
 	return i.instance
 }
 
+// GetNewInstance returns an instance that shares no pointers with the registered one.
+// Instances implementing IInstanceFactory or Cloner build it themselves; others are deep copied.
 func (i Injector[T]) GetNewInstance() T {
-	n := new(T)
-	*n = i.instance
-	return *n
+	copied, err := clone.Deep(i.instance)
+	if err != nil {
+		logger.Load().Error("new instance failed", slog.String("service", i.incjetionName), slog.String("error", err.Error()))
+		log.Fatalf("Cannot create a new instance of %s: %v", i.incjetionName, err)
+	}
+	return copied.(T)
 }
 
 func (i Injector[T]) MatchWithName(name string) bool {
@@ -1,5 +1,12 @@
 package sioc
 
+import "github.com/sergiodii/sioc/extension/clone"
+
 type IInjector interface {
 	InjectorInsertion() any
 }
+
+// IInstanceFactory is implemented by services that build their own new instances for
+// Init parameters marked with InitializeNewInstanceTo, instead of being deep copied. It is
+// the clone.InstanceFactory that v1 honors as well, next to Cloner.
+type IInstanceFactory = clone.InstanceFactory
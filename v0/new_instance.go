@@ -1,7 +1,11 @@
 package sioc
 
-type InitializeNewInstanceTo string
+import v1 "github.com/sergiodii/sioc/v1"
 
-const (
-	NEW InitializeNewInstanceTo = "NEW"
-)
+// InitializeNewInstanceTo is the v1 InstanceCreationMode, so Init methods that use it
+// also work on a v1 container set with UseContainer.
+type InitializeNewInstanceTo = v1.InstanceCreationMode
+
+// NEW is the v1 CreateNewInstance, so Init methods comparing their marker to it behave the
+// same on both. Its value is "CREATE_NEW", no longer "NEW"; compare with the constant.
+const NEW = v1.CreateNewInstance
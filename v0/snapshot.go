@@ -0,0 +1,48 @@
+package sioc
+
+import (
+	v1 "github.com/sergiodii/sioc/v1"
+)
+
+// InjectorSnapshot is the state of the global injector saved by Snapshot.
+type InjectorSnapshot struct {
+	items       []*Injector[interface{}]
+	initialized []bool
+	container   v1.ServiceContainer
+}
+
+// Snapshot saves the registered services, their init status and the container set with
+// UseContainer, so tests can roll the global injector back with Restore:
+//
+//	defer sioc.Restore(sioc.Snapshot())
+//
+// The services of a container set with UseContainer are not saved; use v1.Snapshot for them.
+func Snapshot() *InjectorSnapshot {
+	Start()
+	initMutex.Lock()
+	defer initMutex.Unlock()
+	snapshot := &InjectorSnapshot{items: inj.items(), container: currentContainer()}
+	for _, item := range snapshot.items {
+		snapshot.initialized = append(snapshot.initialized, item.Initialized())
+	}
+	return snapshot
+}
+
+// Restore rolls the global injector back to the snapshot: services registered since are
+// removed, init status is reset, so Init runs again for services initialized since, and
+// the container set with UseContainer is put back. The services' own fields are not restored.
+func Restore(snapshot *InjectorSnapshot) {
+	if snapshot == nil {
+		return
+	}
+	Start()
+	initMutex.Lock()
+	defer initMutex.Unlock()
+	for index, item := range snapshot.items {
+		item.initialized = snapshot.initialized[index]
+	}
+	inj.mutex.Lock()
+	inj.List = append([]*Injector[interface{}](nil), snapshot.items...)
+	inj.mutex.Unlock()
+	UseContainer(snapshot.container)
+}
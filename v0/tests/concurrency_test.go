@@ -0,0 +1,108 @@
+package sioc_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sergiodii/sioc/extension/logging"
+	"github.com/sergiodii/sioc/v0"
+)
+
+const concurrentWorkers = 32
+
+type TestCountingInit struct {
+	calls atomic.Int32
+}
+
+func (t *TestCountingInit) Init(_ *TestStruct) {
+	t.calls.Add(1)
+}
+
+func runConcurrently(workers int, work func(worker int)) {
+	var waitGroup sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		waitGroup.Add(1)
+		go func(worker int) {
+			defer waitGroup.Done()
+			work(worker)
+		}(worker)
+	}
+	waitGroup.Wait()
+}
+
+func TestConcurrentRegisterAndGet(t *testing.T) {
+	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
+	sioc.Register(&TestStructWithInit{})
+
+	runConcurrently(concurrentWorkers, func(worker int) {
+		if worker%2 == 0 {
+			sioc.Register(&TestStruct{Name: "concurrent"})
+			return
+		}
+		if sioc.Get[*TestStructWithInit]() == nil {
+			t.Error("Expected the service registered up front")
+		}
+		sioc.Len()
+	})
+
+	if sioc.Len() != 1+concurrentWorkers/2 {
+		t.Errorf("Expected %d services, got %d", 1+concurrentWorkers/2, sioc.Len())
+	}
+}
+
+func TestConcurrentInitRunsInitOnce(t *testing.T) {
+	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
+	counting := &TestCountingInit{}
+	sioc.Register(counting)
+	sioc.Register(&TestStruct{Name: "dependency"})
+
+	runConcurrently(concurrentWorkers, func(int) {
+		sioc.Init()
+	})
+
+	if calls := counting.calls.Load(); calls != 1 {
+		t.Errorf("Expected Init to run once, ran %d times", calls)
+	}
+}
+
+func TestConcurrentRegisterDuringInit(t *testing.T) {
+	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
+	sioc.Register(&TestStruct{Name: "dependency"})
+
+	runConcurrently(concurrentWorkers, func(worker int) {
+		switch worker % 3 {
+		case 0:
+			sioc.Register(&TestCountingInit{})
+		case 1:
+			sioc.Init()
+		default:
+			sioc.Get[TestStruct]()
+		}
+	})
+	sioc.Init()
+
+	if sioc.Len() != 1+(concurrentWorkers+2)/3 {
+		t.Errorf("Expected %d services, got %d", 1+(concurrentWorkers+2)/3, sioc.Len())
+	}
+}
+
+func TestConcurrentConfiguration(t *testing.T) {
+	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
+	defer sioc.SetLogHandler(nil)
+
+	runConcurrently(concurrentWorkers, func(worker int) {
+		switch worker % 3 {
+		case 0:
+			sioc.SetLogHandler(logging.Discard)
+		case 1:
+			sioc.UseContainer(nil)
+		default:
+			sioc.Register(&TestStruct{})
+		}
+	})
+}
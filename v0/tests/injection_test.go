@@ -1,10 +1,14 @@
 package sioc_test
 
 import (
+	"bytes"
+	"log/slog"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/sergiodii/sioc/v0"
+	v1 "github.com/sergiodii/sioc/v1"
 )
 
 type TestStruct struct {
@@ -46,39 +50,40 @@ func TestStartShouldInitializeInjector(t *testing.T) {
 
 func TestInjectShouldAddInstance(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	testStruct := &TestStruct{Name: "test"}
 	sioc.Register(testStruct)
 
 	if sioc.Len() != 1 {
 		t.Error("Expected one instance after Inject()")
 	}
-	sioc.ClearList()
 }
 
 func TestGetShouldReturnInjectedInstance(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	testStruct := &TestStruct{Name: "test"}
 	sioc.Register(testStruct)
 	result := sioc.Get[*TestStruct]()
 	if result.Name != "test" {
 		t.Error("Expected to get injected instance")
 	}
-	sioc.ClearList()
 }
 
 func TestInjectWithInjectorInterface(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	testInjector := &TestInjector{}
 	sioc.Register(testInjector)
 
 	if sioc.Len() != 2 {
 		t.Error("Expected two instances after injecting IInjector, has: ", sioc.Len())
 	}
-	sioc.ClearList()
 }
 
 func TestGetInjectedFromInjector(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	testInjector := &TestInjector{}
 	sioc.Register(testInjector)
 	result := sioc.Get[TestStruct]()
@@ -89,6 +94,7 @@ func TestGetInjectedFromInjector(t *testing.T) {
 
 func TestInitShouldCallInitMethod(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	testStruct := &TestStructWithInit{}
 	sioc.Register(testStruct)
 	sioc.Init()
@@ -99,6 +105,7 @@ func TestInitShouldCallInitMethod(t *testing.T) {
 
 func TestGetWithInterface(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	testStruct := &TestStructWithInterface{Name: "interface"}
 	sioc.Register(testStruct)
 	result := sioc.Get[ITestInterface]()
@@ -158,6 +165,7 @@ func (t *TestStructWithMultipleDeps) Init(dep1 *TestStruct, dep2 ITestInterface)
 
 func TestInitWithNoDependencies(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	testStruct := &TestStructWithInit{}
 	sioc.Register(testStruct)
 	sioc.Init()
@@ -168,6 +176,7 @@ func TestInitWithNoDependencies(t *testing.T) {
 
 func TestInitWithOneDependency(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	dep := &TestStruct{Name: "dependency"}
 	testStruct := &TestStructWithDependency{}
 
@@ -186,6 +195,7 @@ func TestInitWithOneDependency(t *testing.T) {
 
 func TestInitWithMultipleDependencies(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	dep1 := &TestStruct{Name: "dep1"}
 	dep2 := &TestStructWithInterface{Name: "dep2"}
 	testStruct := &TestStructWithMultipleDeps{}
@@ -209,6 +219,7 @@ func TestInitWithMultipleDependencies(t *testing.T) {
 
 func TestInitOrder(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	dep := &TestStructWithInit{}
 	testStruct := &TestStructWithInit{}
 
@@ -236,6 +247,7 @@ func (t *TestStructDependent) Init(depNew *TestStructWithDependencyValue) {
 
 func TestValueChangeReflectsDependency1(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	dep := &TestStructWithDependencyValue{Value: "initial-value"}
 	dependent := &TestStructDependent{}
 
@@ -251,6 +263,7 @@ func TestValueChangeReflectsDependency1(t *testing.T) {
 
 func TestValueChangeReflectsDependency2(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	dep := &TestStructWithDependencyValue{Value: "test1"}
 	dependent := &TestStructDependent{}
 
@@ -265,6 +278,7 @@ func TestValueChangeReflectsDependency2(t *testing.T) {
 }
 
 func TestValueChangeReflectsDependency3(t *testing.T) {
+	defer sioc.Restore(sioc.Snapshot())
 	os.Setenv("NODE_ENV", "test")
 	sioc.Start()
 	dep := &TestStructWithDependencyValue{Value: "abc"}
@@ -284,6 +298,7 @@ func TestValueChangeReflectsDependency3(t *testing.T) {
 
 func TestValueChangeReflectsDependency4(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	dep := &TestStructWithDependencyValue{Value: "initial"}
 	dependent := &TestStructDependent{}
 
@@ -302,6 +317,7 @@ func TestValueChangeReflectsDependency4(t *testing.T) {
 
 func TestValueChangeReflectsDependency5(t *testing.T) {
 	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
 	dep := &TestStructWithDependencyValue{Value: ""}
 	dependent := &TestStructDependent{}
 
@@ -330,6 +346,7 @@ func (t *TestNewInstance) Init(_ sioc.InitializeNewInstanceTo, a *TestStructWith
 }
 
 func TestNewInstanceUsingNewInstanceTo(t *testing.T) {
+	defer sioc.Restore(sioc.Snapshot())
 	os.Setenv("NODE_ENV", "test")
 	sioc.Start()
 	depA := &TestStructWithDependencyValue{Value: "test"}
@@ -365,6 +382,7 @@ func (t *TestNewInstanceTwo) Init(a *TestStructWithDependencyValue, _ sioc.Initi
 }
 
 func TestNewInstanceUsingNewInstanceToB(t *testing.T) {
+	defer sioc.Restore(sioc.Snapshot())
 	os.Setenv("NODE_ENV", "test")
 	sioc.Start()
 	depA := &TestStructWithDependencyValue{Value: "test"}
@@ -385,3 +403,216 @@ func TestNewInstanceUsingNewInstanceToB(t *testing.T) {
 		t.Error("Expected value of a to be changed")
 	}
 }
+
+type TestNestedDependency struct {
+	Settings map[string]string
+	Tags     []string
+	Inner    *TestStructWithDependencyValue
+	counter  int
+}
+
+type TestNewNestedInstance struct {
+	shared *TestNestedDependency
+	copied *TestNestedDependency
+}
+
+func (t *TestNewNestedInstance) Init(shared *TestNestedDependency, _ sioc.InitializeNewInstanceTo, copied *TestNestedDependency) {
+	t.shared = shared
+	t.copied = copied
+	copied.Settings["mode"] = "copy"
+	copied.Tags[0] = "copy"
+	copied.Inner.Value = "copy"
+	copied.counter++
+}
+
+func TestNewInstanceDoesNotShareSingleton(t *testing.T) {
+	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
+	singleton := &TestNestedDependency{
+		Settings: map[string]string{"mode": "singleton"},
+		Tags:     []string{"singleton"},
+		Inner:    &TestStructWithDependencyValue{Value: "singleton"},
+	}
+	sioc.Register(singleton)
+	sioc.Register(&TestNewNestedInstance{})
+	sioc.Init()
+
+	module := sioc.Get[*TestNewNestedInstance]()
+	if module.shared != singleton {
+		t.Error("Expected the unmarked parameter to receive the singleton")
+	}
+	if module.copied == singleton || module.copied.Inner == singleton.Inner {
+		t.Fatal("Expected the marked parameter to receive a new instance")
+	}
+	if singleton.Settings["mode"] != "singleton" || singleton.Tags[0] != "singleton" || singleton.Inner.Value != "singleton" || singleton.counter != 0 {
+		t.Errorf("Expected the singleton to be unchanged, got %+v", singleton)
+	}
+	if module.copied.Inner.Value != "copy" || module.copied.counter != 1 {
+		t.Errorf("Expected the changes on the new instance, got %+v", module.copied)
+	}
+}
+
+type TestFactoryDependency struct {
+	Created bool
+}
+
+func (t *TestFactoryDependency) NewInstance() any {
+	return &TestFactoryDependency{Created: true}
+}
+
+type TestNewFactoryInstance struct {
+	dependency *TestFactoryDependency
+}
+
+func (t *TestNewFactoryInstance) Init(_ sioc.InitializeNewInstanceTo, dependency *TestFactoryDependency) {
+	t.dependency = dependency
+}
+
+func TestNewInstanceUsesFactory(t *testing.T) {
+	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
+	sioc.Register(&TestFactoryDependency{})
+	sioc.Register(&TestNewFactoryInstance{})
+	sioc.Init()
+
+	if dependency := sioc.Get[*TestNewFactoryInstance]().dependency; dependency == nil || !dependency.Created {
+		t.Errorf("Expected the instance built by NewInstance, got %+v", dependency)
+	}
+}
+
+func TestSetLogHandlerEmitsEvents(t *testing.T) {
+	defer sioc.Restore(sioc.Snapshot())
+	var buffer bytes.Buffer
+	sioc.SetLogHandler(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	defer sioc.SetLogHandler(nil)
+
+	sioc.Start()
+	sioc.Register(&TestStructWithInit{})
+	sioc.Init()
+
+	output := buffer.String()
+	for _, expected := range []string{"service registered", "service init started", "service init finished", "init finished"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q event to be logged, got: %s", expected, output)
+		}
+	}
+}
+
+func TestUseContainerSharesSingletons(t *testing.T) {
+	defer sioc.Restore(sioc.Snapshot())
+	container := v1.NewContainer()
+	sioc.UseContainer(container)
+
+	legacy := &TestStructWithInit{}
+	sioc.Register(legacy)
+	v1.Inject(&TestStructDependent{}, container)
+	sioc.Register(&TestStructWithDependencyValue{Value: "shared"})
+	sioc.Init()
+
+	if v1.Get[*TestStructWithInit](container) != legacy {
+		t.Error("Expected v1 to resolve the service registered through v0")
+	}
+	if !legacy.Initialized {
+		t.Error("Expected the v0 service to be initialized")
+	}
+	dependent := sioc.Get[*TestStructDependent]()
+	if dependent.Dep == nil || dependent.Dep.Value != "shared" {
+		t.Errorf("Expected the v1 service to receive the v0 dependency, got %+v", dependent.Dep)
+	}
+	if value := sioc.Get[TestStructWithDependencyValue](); value.Value != "shared" {
+		t.Errorf("Expected value resolution from a pointer, got %+v", value)
+	}
+	if sioc.Len() != 3 {
+		t.Errorf("Expected 3 services, got %d", sioc.Len())
+	}
+}
+
+func TestUseContainerRegistersInjectorInsertion(t *testing.T) {
+	defer sioc.Restore(sioc.Snapshot())
+	container := v1.NewContainer()
+	sioc.UseContainer(container)
+
+	sioc.Register(&TestInjector{})
+
+	if injected := v1.Get[*TestStruct](container); injected.Name != "injected" {
+		t.Errorf("Expected the inserted service, got %+v", injected)
+	}
+}
+
+func TestUseContainerInitWithNewInstanceTo(t *testing.T) {
+	defer sioc.Restore(sioc.Snapshot())
+	container := v1.NewContainer()
+	sioc.UseContainer(container)
+
+	sioc.Register(&TestNewInstance{})
+	sioc.Register(&TestStructWithDependencyValue{Value: "test"})
+	sioc.Init()
+
+	testModule := sioc.Get[*TestNewInstance]()
+	if !testModule.Initialized || testModule.a == nil {
+		t.Error("Expected module to be initialized with its dependency")
+	}
+}
+
+type TestStructInitOrder struct {
+	DependencyInitialized bool
+	Mode                  sioc.InitializeNewInstanceTo
+}
+
+func (t *TestStructInitOrder) Init(mode sioc.InitializeNewInstanceTo, dep *TestStructWithInit) {
+	t.DependencyInitialized = dep.Initialized
+	t.Mode = mode
+}
+
+func TestUseContainerInitializesDependenciesFirst(t *testing.T) {
+	defer sioc.Restore(sioc.Snapshot())
+	container := v1.NewContainer()
+	sioc.UseContainer(container)
+
+	service := &TestStructInitOrder{}
+	sioc.Register(service)
+	sioc.Register(&TestStructWithInit{})
+	sioc.Init()
+
+	if !service.DependencyInitialized {
+		t.Error("Expected the dependency to be initialized before it was passed to Init, as in v0")
+	}
+	if service.Mode != sioc.NEW {
+		t.Errorf("Expected the marker to equal sioc.NEW, got %q", service.Mode)
+	}
+}
+
+func TestRestoreRollsBackRegistrationsAndInit(t *testing.T) {
+	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
+	withInit := &TestStructWithInit{}
+	sioc.Register(withInit)
+	snapshot := sioc.Snapshot()
+
+	sioc.Register(&TestStruct{Name: "extra"})
+	sioc.Init()
+	container := v1.NewContainer()
+	sioc.UseContainer(container)
+
+	sioc.Restore(snapshot)
+	if sioc.Len() != 1 {
+		t.Fatalf("Expected the registration made after the snapshot to be removed, got %d services", sioc.Len())
+	}
+
+	withInit.Initialized = false
+	sioc.Init()
+	if !withInit.Initialized {
+		t.Error("Expected Init to run again after Restore")
+	}
+}
+
+func TestClearListRemovesServices(t *testing.T) {
+	sioc.Start()
+	defer sioc.Restore(sioc.Snapshot())
+	sioc.Register(&TestStruct{Name: "cleared"})
+
+	sioc.ClearList()
+	if sioc.Len() != 0 {
+		t.Errorf("Expected no services after ClearList, got %d", sioc.Len())
+	}
+}
@@ -0,0 +1,291 @@
+package sioc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigSource fills a configuration struct from a single source.
+type ConfigSource interface {
+	Load(target any) error
+}
+
+// configSourceFunc is a built-in source. Besides filling the target, it records in present
+// the paths of the fields the source holds a value for, so required fields set to their zero
+// value, such as an explicit false, are not reported as missing.
+type configSourceFunc func(target any, present map[string]bool) error
+
+func (sf configSourceFunc) Load(target any) error {
+	return sf(target, map[string]bool{})
+}
+
+// FromEnv fills the fields tagged with `env:"NAME"` from environment variables.
+// Unset variables leave the field untouched.
+func FromEnv() ConfigSource {
+	return configSourceFunc(func(target any, present map[string]bool) error {
+		var loadErrors []error
+		walkConfigFields(reflect.ValueOf(target).Elem(), "", func(field reflect.Value, structField reflect.StructField, path string) {
+			name := structField.Tag.Get("env")
+			if name == "" {
+				return
+			}
+			value, found := os.LookupEnv(name)
+			if !found {
+				return
+			}
+			present[path] = true
+			if err := setConfigField(field, value); err != nil {
+				loadErrors = append(loadErrors, fmt.Errorf("%s: env %s: %w", path, name, err))
+			}
+		})
+		return errors.Join(loadErrors...)
+	})
+}
+
+// FromJSONFile fills the struct from a JSON file, using the struct's json tags.
+func FromJSONFile(path string) ConfigSource {
+	return configSourceFunc(func(target any, present map[string]bool) error {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(content, target); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		var document map[string]any
+		if json.Unmarshal(content, &document) == nil {
+			markPresentFields(reflect.ValueOf(target).Elem(), "", document, "json", present)
+		}
+		return nil
+	})
+}
+
+// FromYAMLFile fills the struct from a YAML file, using the struct's yaml tags.
+func FromYAMLFile(path string) ConfigSource {
+	return configSourceFunc(func(target any, present map[string]bool) error {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := yaml.Unmarshal(content, target); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		var document map[string]any
+		if yaml.Unmarshal(content, &document) == nil {
+			markPresentFields(reflect.ValueOf(target).Elem(), "", document, "yaml", present)
+		}
+		return nil
+	})
+}
+
+// BindConfig builds a *T, registers it in the container and returns it. Fields are first set
+// from their `default:"..."` tags, then filled by each source in order, so later sources win.
+// Without sources, FromEnv is used. Fields tagged `required:"true"` must have a default or be
+// set by a source, even to their zero value; with custom sources, which cannot tell, a
+// non-zero value also satisfies them.
+// Every source and validation error is collected and returned together, and nothing is
+// registered when an error occurs.
+func BindConfig[T any](serviceContainer ServiceContainer, sources ...ConfigSource) (*T, error) {
+	config := new(T)
+	configValue := reflect.ValueOf(config).Elem()
+	if configValue.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sioc: cannot bind config %T: not a struct", *config)
+	}
+	if len(sources) == 0 {
+		sources = []ConfigSource{FromEnv()}
+	}
+
+	var bindErrors []error
+	present := map[string]bool{}
+	walkConfigFields(configValue, "", func(field reflect.Value, structField reflect.StructField, path string) {
+		defaultValue, found := structField.Tag.Lookup("default")
+		if !found {
+			return
+		}
+		present[path] = true
+		if err := setConfigField(field, defaultValue); err != nil {
+			bindErrors = append(bindErrors, fmt.Errorf("%s: default: %w", path, err))
+		}
+	})
+
+	customSources := false
+	for _, source := range sources {
+		var err error
+		if builtIn, ok := source.(configSourceFunc); ok {
+			err = builtIn(config, present)
+		} else {
+			customSources = true
+			err = source.Load(config)
+		}
+		if err != nil {
+			bindErrors = append(bindErrors, err)
+		}
+	}
+
+	walkConfigFields(configValue, "", func(field reflect.Value, structField reflect.StructField, path string) {
+		if structField.Tag.Get("required") != "true" || present[path] {
+			return
+		}
+		if !customSources || field.IsZero() {
+			bindErrors = append(bindErrors, fmt.Errorf("%s: required value is missing", path))
+		}
+	})
+
+	configType := configValue.Type()
+	if err := errors.Join(bindErrors...); err != nil {
+		err = fmt.Errorf("sioc: invalid config %v:\n%w", configType, err)
+		eventsOf(serviceContainer).failed("config binding failed", configType, err)
+		return nil, err
+	}
+
+	Inject(config, serviceContainer)
+	return config, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// walkConfigFields calls visit for every exported field, descending into nested structs.
+func walkConfigFields(structValue reflect.Value, prefix string, visit func(reflect.Value, reflect.StructField, string)) {
+	structType := structValue.Type()
+	if prefix == "" {
+		prefix = structType.Name()
+	}
+	for index := 0; index < structType.NumField(); index++ {
+		structField := structType.Field(index)
+		if !structField.IsExported() {
+			continue
+		}
+		field := structValue.Field(index)
+		path := prefix + "." + structField.Name
+		if field.Kind() == reflect.Struct && field.Type() != durationType && !hasConfigTags(structField) {
+			walkConfigFields(field, path, visit)
+			continue
+		}
+		visit(field, structField, path)
+	}
+}
+
+// markPresentFields records the paths of the struct fields the decoded document holds a
+// value for, naming keys like the json or yaml decoder does.
+func markPresentFields(structValue reflect.Value, prefix string, document map[string]any, tagName string, present map[string]bool) {
+	structType := structValue.Type()
+	if prefix == "" {
+		prefix = structType.Name()
+	}
+	for index := 0; index < structType.NumField(); index++ {
+		structField := structType.Field(index)
+		if !structField.IsExported() {
+			continue
+		}
+		value, found := documentValue(document, structField, tagName)
+		if !found {
+			continue
+		}
+		field := structValue.Field(index)
+		path := prefix + "." + structField.Name
+		if field.Kind() == reflect.Struct && field.Type() != durationType && !hasConfigTags(structField) {
+			if nested, ok := value.(map[string]any); ok {
+				markPresentFields(field, path, nested, tagName, present)
+			}
+			continue
+		}
+		present[path] = true
+	}
+}
+
+// documentValue returns the value of the field's key. encoding/json matches keys without
+// regard to case, and yaml.v3 defaults to the lowercased field name.
+func documentValue(document map[string]any, structField reflect.StructField, tagName string) (any, bool) {
+	key, _, _ := strings.Cut(structField.Tag.Get(tagName), ",")
+	if key == "-" {
+		return nil, false
+	}
+	if tagName == "yaml" {
+		if key == "" {
+			key = strings.ToLower(structField.Name)
+		}
+		value, found := document[key]
+		return value, found
+	}
+	if key == "" {
+		key = structField.Name
+	}
+	for documentKey, value := range document {
+		if strings.EqualFold(documentKey, key) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func hasConfigTags(structField reflect.StructField) bool {
+	for _, tag := range []string{"env", "default", "required"} {
+		if _, found := structField.Tag.Lookup(tag); found {
+			return true
+		}
+	}
+	return false
+}
+
+// setConfigField parses the text into the field according to its kind.
+func setConfigField(field reflect.Value, text string) error {
+	if field.Type() == durationType {
+		duration, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		field.SetBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(text, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(text, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(value)
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(text, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(value)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %v", field.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(text, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items).Convert(field.Type()))
+	default:
+		return fmt.Errorf("unsupported type %v", field.Type())
+	}
+	return nil
+}
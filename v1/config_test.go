@@ -0,0 +1,152 @@
+package sioc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DatabaseConfig struct {
+	URL      string        `env:"SIOC_TEST_DB_URL" json:"url" yaml:"url" required:"true"`
+	PoolSize int           `env:"SIOC_TEST_DB_POOL" json:"poolSize" yaml:"poolSize" default:"4"`
+	Timeout  time.Duration `env:"SIOC_TEST_DB_TIMEOUT" default:"5s"`
+	Replicas []string      `env:"SIOC_TEST_DB_REPLICAS"`
+	TLS      struct {
+		Enabled bool `env:"SIOC_TEST_DB_TLS" json:"enabled" yaml:"enabled"`
+	} `json:"tls" yaml:"tls"`
+}
+
+type configConsumer struct {
+	config *DatabaseConfig
+}
+
+func (cc *configConsumer) Init(config *DatabaseConfig) {
+	cc.config = config
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// TestBindConfigFromEnv tests defaults, environment variables and registration
+func TestBindConfigFromEnv(t *testing.T) {
+	t.Setenv("SIOC_TEST_DB_URL", "postgres://localhost/app")
+	t.Setenv("SIOC_TEST_DB_REPLICAS", "replica-1, replica-2")
+	t.Setenv("SIOC_TEST_DB_TLS", "true")
+	container := NewContainer()
+
+	config, err := BindConfig[DatabaseConfig](container)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if config.URL != "postgres://localhost/app" || config.PoolSize != 4 || config.Timeout != 5*time.Second {
+		t.Errorf("Expected env and default values, got %+v", config)
+	}
+	if len(config.Replicas) != 2 || config.Replicas[1] != "replica-2" {
+		t.Errorf("Expected comma-separated replicas, got %v", config.Replicas)
+	}
+	if !config.TLS.Enabled {
+		t.Error("Expected nested struct fields to be bound")
+	}
+
+	consumer := &configConsumer{}
+	Inject(consumer, container)
+	Init(container)
+	if consumer.config != config {
+		t.Error("Expected the bound config to be injected into Init")
+	}
+}
+
+// TestBindConfigSourcesOverride tests that later sources override earlier ones
+func TestBindConfigSourcesOverride(t *testing.T) {
+	jsonPath := writeConfigFile(t, "config.json", `{"url": "json-url", "poolSize": 8}`)
+	yamlPath := writeConfigFile(t, "config.yaml", "url: yaml-url\ntls:\n  enabled: true\n")
+	t.Setenv("SIOC_TEST_DB_POOL", "16")
+
+	config, err := BindConfig[DatabaseConfig](NewContainer(), FromJSONFile(jsonPath), FromYAMLFile(yamlPath), FromEnv())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if config.URL != "yaml-url" {
+		t.Errorf("Expected YAML to override JSON, got %v", config.URL)
+	}
+	if config.PoolSize != 16 {
+		t.Errorf("Expected env to override JSON, got %v", config.PoolSize)
+	}
+	if !config.TLS.Enabled {
+		t.Error("Expected YAML nested value to be loaded")
+	}
+}
+
+// TestBindConfigAggregatesErrors tests that every error is reported and nothing is registered
+func TestBindConfigAggregatesErrors(t *testing.T) {
+	t.Setenv("SIOC_TEST_DB_POOL", "many")
+	t.Setenv("SIOC_TEST_DB_TIMEOUT", "soon")
+	recorder := NewHookRecorder()
+	container := NewContainer(WithHooks(recorder))
+
+	config, err := BindConfig[DatabaseConfig](container, FromEnv(), FromJSONFile(filepath.Join(t.TempDir(), "missing.json")))
+	if err == nil {
+		t.Fatal("Expected binding to fail")
+	}
+	if config != nil {
+		t.Error("Expected no config on failure")
+	}
+
+	for _, expected := range []string{"DatabaseConfig.URL: required", "SIOC_TEST_DB_POOL", "SIOC_TEST_DB_TIMEOUT", "missing.json"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to mention %q, got: %v", expected, err)
+		}
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Error("Expected source errors to be wrapped")
+	}
+	if container.Count() != 0 {
+		t.Error("Invalid config must not be registered")
+	}
+	if len(recorder.EventsOf(HookError)) != 1 {
+		t.Error("Expected the binding failure to be reported to hooks")
+	}
+}
+
+// TestBindConfigRejectsNonStructs tests that only structs can be bound
+func TestBindConfigRejectsNonStructs(t *testing.T) {
+	if _, err := BindConfig[string](NewContainer()); err == nil {
+		t.Error("Expected binding a non-struct to fail")
+	}
+}
+
+type FeatureToggles struct {
+	Enabled bool `env:"SIOC_TEST_FEATURE_ENABLED" json:"enabled" yaml:"enabled" required:"true"`
+	Retries int  `env:"SIOC_TEST_FEATURE_RETRIES" json:"retries" yaml:"retries" required:"true"`
+}
+
+// TestBindConfigRequiredAcceptsZeroValues tests that required fields set to their zero value are present
+func TestBindConfigRequiredAcceptsZeroValues(t *testing.T) {
+	t.Setenv("SIOC_TEST_FEATURE_ENABLED", "false")
+	t.Setenv("SIOC_TEST_FEATURE_RETRIES", "0")
+	if _, err := BindConfig[FeatureToggles](NewContainer()); err != nil {
+		t.Errorf("Expected explicit zero values from env to satisfy required, got %v", err)
+	}
+
+	jsonPath := writeConfigFile(t, "features.json", `{"ENABLED": false, "retries": 0}`)
+	if _, err := BindConfig[FeatureToggles](NewContainer(), FromJSONFile(jsonPath)); err != nil {
+		t.Errorf("Expected explicit zero values from JSON to satisfy required, got %v", err)
+	}
+
+	yamlPath := writeConfigFile(t, "features.yaml", "enabled: false\n")
+	_, err := BindConfig[FeatureToggles](NewContainer(), FromYAMLFile(yamlPath))
+	if err == nil || !strings.Contains(err.Error(), "FeatureToggles.Retries: required") || strings.Contains(err.Error(), "Enabled") {
+		t.Errorf("Expected only the absent key to be missing, got %v", err)
+	}
+}
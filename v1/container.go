@@ -1,9 +1,10 @@
 package sioc
 
 import (
+	"reflect"
 	"sync"
-
-	"github.com/sergiodii/sioc/extension/text"
+	"sync/atomic"
+	"time"
 )
 
 // ServiceContainer defines the interface for a dependency injection container.
@@ -16,36 +17,274 @@ type ServiceContainer interface {
 
 // serviceRegistry implements the ServiceContainer interface using sync.Map for concurrency safety.
 type serviceRegistry struct {
+	// generation is bumped on every registration. It is kept first so 64-bit
+	// atomic access stays aligned on 32-bit platforms.
+	generation uint64
+
+	// services maps service keys to *serviceEntry values.
 	services sync.Map
+
+	// resolutions caches Get lookups by target type. Entries are tagged with the
+	// generation they were resolved at and ignored once a registration bumps it.
+	resolutions sync.Map
+
+	events containerEvents
+
+	// parent is set for scopes; lookups that miss this registry fall back to it.
+	parent ServiceContainer
+	// closed is set when the scope is closed, so lookups no longer fall back to the parent.
+	closed atomic.Bool
+	// defaultLifetime is the lifetime given to services registered directly in this registry.
+	defaultLifetime Lifetime
+	// profiles are the active profiles used by conditional registrations.
+	profiles []string
+	// repanic leaves panics in user code unrecovered; see WithRepanic.
+	repanic bool
+}
+
+// serviceEntry is a registered service instance together with its lifecycle state.
+type serviceEntry struct {
+	key      string
+	instance any
+	lifetime Lifetime
+
+	mutex        sync.Mutex
+	initialized  bool
+	initDuration time.Duration
+	dependencies []reflect.Type
+	// provider is set while a ServiceProvider waits for its Init method before providing.
+	provider ServiceProvider
+	// provisioning is set while a lookup initializes the pending provider, and closed when it is done.
+	provisioning chan struct{}
+	// retry is the policy applied when the service's Init method returns an error.
+	retry retryPolicy
+	// initErr is the error Init gave up with, returned to later lookups without calling Init again.
+	initErr error
+	// watchers are notified with the new instance when the service is swapped.
+	watchers    map[uint64]func(any)
+	nextWatcher uint64
+}
+
+// markInitialized records a successful Init call and the dependencies it received.
+func (se *serviceEntry) markInitialized(duration time.Duration, dependencies []reflect.Type) {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	se.initialized = true
+	se.initDuration = duration
+	se.dependencies = dependencies
+	se.initErr = nil
+}
+
+// recordInitFailure records the error Init gave up with.
+func (se *serviceEntry) recordInitFailure(err error) {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	se.initErr = err
+}
+
+// clearInitFailure forgets a recorded failure, so Init may be called again.
+func (se *serviceEntry) clearInitFailure() {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	se.initErr = nil
+}
+
+// initFailure returns the error Init gave up with, if it has not succeeded since.
+func (se *serviceEntry) initFailure() error {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	return se.initErr
+}
+
+// pendingProviderFailure returns the recorded failure of a provider that is still pending.
+func (se *serviceEntry) pendingProviderFailure() error {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	if se.provider == nil {
+		return nil
+	}
+	return se.initErr
+}
+
+// setRetryPolicy sets the policy applied when Init fails.
+func (se *serviceEntry) setRetryPolicy(retry retryPolicy) {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	se.retry = retry
+}
+
+// retryPolicy returns the policy applied when Init fails. Entries default to a single attempt.
+func (se *serviceEntry) retryPolicy() retryPolicy {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	if se.retry.attempts < 1 {
+		return retryPolicy{attempts: 1}
+	}
+	return se.retry
+}
+
+// isInitialized reports whether Init already ran for the entry.
+func (se *serviceEntry) isInitialized() bool {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	return se.initialized
+}
+
+// setPendingProvider defers the provider's provision until its Init method has run.
+func (se *serviceEntry) setPendingProvider(provider ServiceProvider) {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	se.provider = provider
+}
+
+// claimProvision reports whether the caller may initialize the pending provider, and if so
+// marks it as being initialized until releaseProvision. A provider whose Init gave up is not
+// claimed again.
+func (se *serviceEntry) claimProvision() bool {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	if se.provider == nil || se.initialized || se.provisioning != nil || se.initErr != nil {
+		return false
+	}
+	se.provisioning = make(chan struct{})
+	return true
+}
+
+// provisionDone returns a channel closed when the provision in progress ends, or nil when
+// the provider is not being initialized.
+func (se *serviceEntry) provisionDone() <-chan struct{} {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	return se.provisioning
+}
+
+// releaseProvision ends the initialization started by claimProvision.
+func (se *serviceEntry) releaseProvision() {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	close(se.provisioning)
+	se.provisioning = nil
+}
+
+// takePendingProvider returns the pending provider, at most once.
+func (se *serviceEntry) takePendingProvider() ServiceProvider {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	provider := se.provider
+	se.provider = nil
+	return provider
+}
+
+// cachedResolution is a resolved accessor together with the registry generation it belongs to.
+type cachedResolution struct {
+	generation uint64
+	accessor   any
 }
 
-// NewContainer creates a new, empty service container instance.
-func NewContainer() ServiceContainer {
-	return &serviceRegistry{}
+// NewContainer creates a new, empty service container instance configured by the given options.
+func NewContainer(options ...ContainerOption) ServiceContainer {
+	registry := &serviceRegistry{
+		events:          containerEvents{logger: discardEvents.logger},
+		defaultLifetime: Singleton,
+		profiles:        profilesFromEnv(),
+	}
+	for _, option := range options {
+		option(registry)
+	}
+	return registry
 }
 
-// Register stores a service instance in the container under the given key.
+// Register stores a service instance in the container under the given key, as is. Inject and
+// Get use the canonical keys built by the typekey package; keys are no longer stripped of "*",
+// so services registered by hand must use those keys to be found by Get.
 func (sr *serviceRegistry) Register(serviceKey string, serviceInstance any) {
-	sr.services.Store(text.Sanitize(serviceKey), serviceInstance)
+	lifetime := sr.defaultLifetime
+	if _, pooled := serviceInstance.(*pooledService); pooled {
+		lifetime = Pooled
+	}
+	sr.services.Store(serviceKey, &serviceEntry{key: serviceKey, instance: serviceInstance, lifetime: lifetime})
+	atomic.AddUint64(&sr.generation, 1)
 }
 
 // Resolve retrieves a service instance by key. Returns (nil, false) if not found.
 func (sr *serviceRegistry) Resolve(serviceKey string) (any, bool) {
-	return sr.services.Load(text.Sanitize(serviceKey))
+	entry, found := sr.entry(serviceKey)
+	if !found {
+		if parent := sr.fallback(); parent != nil {
+			return parent.Resolve(serviceKey)
+		}
+		return nil, false
+	}
+	return entry.instance, true
+}
+
+// fallback returns the parent that lookups missing this registry fall back to, or nil for a
+// root container or a closed scope.
+func (sr *serviceRegistry) fallback() ServiceContainer {
+	if sr.closed.Load() {
+		return nil
+	}
+	return sr.parent
 }
 
 // ListAll returns a slice of all registered service instances.
+// Scopes list their own services first, followed by the parent services they do not shadow.
 func (sr *serviceRegistry) ListAll() []any {
 	var serviceList []any
-	sr.services.Range(func(_, serviceInstance any) bool {
-		serviceList = append(serviceList, serviceInstance)
+	for _, entry := range sr.visibleEntries() {
+		serviceList = append(serviceList, entry.instance)
+	}
+	if parent := sr.fallback(); parent != nil {
+		if _, ok := registryOf(parent); !ok {
+			serviceList = append(serviceList, parent.ListAll()...)
+		}
+	}
+	return serviceList
+}
+
+// visibleEntries returns the entries visible from the registry, including unshadowed parent entries.
+func (sr *serviceRegistry) visibleEntries() []*serviceEntry {
+	entryList := sr.entries()
+	parentRegistry, ok := registryOf(sr.fallback())
+	if !ok {
+		return entryList
+	}
+	ownKeys := make(map[string]bool, len(entryList))
+	for _, entry := range entryList {
+		ownKeys[entry.key] = true
+	}
+	for _, entry := range parentRegistry.visibleEntries() {
+		if !ownKeys[entry.key] {
+			entryList = append(entryList, entry)
+		}
+	}
+	return entryList
+}
+
+// entry returns the entry registered directly in this registry under the key.
+func (sr *serviceRegistry) entry(serviceKey string) (*serviceEntry, bool) {
+	entry, found := sr.services.Load(serviceKey)
+	if !found {
+		return nil, false
+	}
+	return entry.(*serviceEntry), true
+}
+
+// entries returns every registered entry.
+func (sr *serviceRegistry) entries() []*serviceEntry {
+	var entryList []*serviceEntry
+	sr.services.Range(func(_, entry any) bool {
+		entryList = append(entryList, entry.(*serviceEntry))
 		return true
 	})
-	return serviceList
+	return entryList
 }
 
-// Count returns the number of registered service instances.
+// Count returns the number of registered service instances, including those visible from a parent.
 func (sr *serviceRegistry) Count() int {
+	if sr.fallback() != nil {
+		return len(sr.ListAll())
+	}
 	serviceCount := 0
 	sr.services.Range(func(_, _ any) bool {
 		serviceCount++
@@ -53,3 +292,71 @@ func (sr *serviceRegistry) Count() int {
 	})
 	return serviceCount
 }
+
+// currentGeneration returns the registration generation used to tag cached resolutions.
+// Scopes add their parent's generation, so registrations in the parent also invalidate them.
+func (sr *serviceRegistry) currentGeneration() uint64 {
+	generation := atomic.LoadUint64(&sr.generation)
+	if parentRegistry, ok := registryOf(sr.parent); ok {
+		generation += parentRegistry.currentGeneration()
+	}
+	return generation
+}
+
+// cachedResolution returns the accessor cached for the target type, if it is still current.
+func (sr *serviceRegistry) cachedResolution(targetType reflect.Type) (any, bool) {
+	entry, found := sr.resolutions.Load(targetType)
+	if !found {
+		return nil, false
+	}
+	resolution := entry.(*cachedResolution)
+	if resolution.generation != sr.currentGeneration() {
+		return nil, false
+	}
+	return resolution.accessor, true
+}
+
+// cacheResolution stores the accessor resolved for the target type at the given generation.
+func (sr *serviceRegistry) cacheResolution(targetType reflect.Type, generation uint64, accessor any) {
+	sr.resolutions.Store(targetType, &cachedResolution{generation: generation, accessor: accessor})
+}
+
+// entryOf returns the entry registered in the container under the key, if the
+// container is a built-in registry.
+func entryOf(serviceContainer ServiceContainer, serviceKey string) (*serviceEntry, bool) {
+	if registry, ok := registryOf(serviceContainer); ok {
+		return registry.entry(serviceKey)
+	}
+	return nil, false
+}
+
+// entriesOf returns the entries of the container. Containers other than the
+// built-in registry get detached entries, so their lifecycle state is not kept.
+func entriesOf(serviceContainer ServiceContainer) []*serviceEntry {
+	if registry, ok := registryOf(serviceContainer); ok {
+		return registry.entries()
+	}
+	var entryList []*serviceEntry
+	for _, serviceInstance := range serviceContainer.ListAll() {
+		entryList = append(entryList, &serviceEntry{instance: serviceInstance, lifetime: Singleton})
+	}
+	return entryList
+}
+
+// registryHolder is implemented by the built-in containers, including scopes that embed a registry.
+type registryHolder interface {
+	registry() *serviceRegistry
+}
+
+func (sr *serviceRegistry) registry() *serviceRegistry {
+	return sr
+}
+
+// registryOf returns the built-in registry behind the container, if any.
+func registryOf(serviceContainer ServiceContainer) (*serviceRegistry, bool) {
+	if holder, ok := serviceContainer.(registryHolder); ok {
+		registry := holder.registry()
+		return registry, registry != nil
+	}
+	return nil, false
+}
@@ -0,0 +1,17 @@
+package sioc
+
+import "context"
+
+// containerContextKey is the context key under which a container is stored.
+type containerContextKey struct{}
+
+// NewContext returns a copy of ctx that carries the container.
+func NewContext(ctx context.Context, serviceContainer ServiceContainer) context.Context {
+	return context.WithValue(ctx, containerContextKey{}, serviceContainer)
+}
+
+// FromContext returns the container stored in ctx by NewContext, if any.
+func FromContext(ctx context.Context) (ServiceContainer, bool) {
+	serviceContainer, ok := ctx.Value(containerContextKey{}).(ServiceContainer)
+	return serviceContainer, ok
+}
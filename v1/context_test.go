@@ -0,0 +1,21 @@
+package sioc
+
+import (
+	"context"
+	"testing"
+)
+
+// TestContextRoundTrip tests storing and retrieving a container from a context
+func TestContextRoundTrip(t *testing.T) {
+	container := NewContainer()
+	ctx := NewContext(context.Background(), container)
+
+	retrieved, ok := FromContext(ctx)
+	if !ok || retrieved != container {
+		t.Errorf("Expected the stored container, got %v (ok: %t)", retrieved, ok)
+	}
+
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("Expected no container in an empty context")
+	}
+}
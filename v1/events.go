@@ -0,0 +1,72 @@
+package sioc
+
+import (
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/sergiodii/sioc/extension/logging"
+)
+
+// containerEvents dispatches lifecycle events to the container's logger and hooks.
+type containerEvents struct {
+	logger *slog.Logger
+	hooks  []Hooks
+}
+
+// discardEvents is used for containers that do not carry their own logger or hooks.
+var discardEvents = &containerEvents{logger: slog.New(logging.Discard)}
+
+// eventsOf returns the event dispatcher configured for the container.
+func eventsOf(serviceContainer ServiceContainer) *containerEvents {
+	if registry, ok := registryOf(serviceContainer); ok {
+		return &registry.events
+	}
+	return discardEvents
+}
+
+// loggerOf returns the structured logger configured for the container.
+func loggerOf(serviceContainer ServiceContainer) *slog.Logger {
+	return eventsOf(serviceContainer).logger
+}
+
+func (ce *containerEvents) registered(serviceType reflect.Type) {
+	ce.logger.Debug("service registered", slog.String("service", serviceType.String()))
+	for _, hooks := range ce.hooks {
+		hooks.OnRegister(serviceType)
+	}
+}
+
+func (ce *containerEvents) resolved(serviceType reflect.Type, cached bool) {
+	if !cached {
+		ce.logger.Debug("service resolved", slog.String("service", serviceType.String()))
+	}
+	for _, hooks := range ce.hooks {
+		hooks.OnResolve(serviceType, cached)
+	}
+}
+
+func (ce *containerEvents) initStarted(serviceType reflect.Type) {
+	ce.logger.Debug("service init started", slog.String("service", serviceType.String()))
+	for _, hooks := range ce.hooks {
+		hooks.OnInitStart(serviceType)
+	}
+}
+
+func (ce *containerEvents) initDone(serviceType reflect.Type, duration time.Duration) {
+	ce.logger.Debug("service init finished", slog.String("service", serviceType.String()), slog.Duration("duration", duration))
+	for _, hooks := range ce.hooks {
+		hooks.OnInitDone(serviceType, duration)
+	}
+}
+
+func (ce *containerEvents) swapped(serviceType reflect.Type) {
+	ce.logger.Info("service swapped", slog.String("service", serviceType.String()))
+}
+
+func (ce *containerEvents) failed(message string, serviceType reflect.Type, err error) {
+	ce.logger.Error(message, slog.String("service", serviceType.String()), slog.Any("error", err))
+	for _, hooks := range ce.hooks {
+		hooks.OnError(serviceType, err)
+	}
+}
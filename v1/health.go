@@ -0,0 +1,205 @@
+package sioc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HealthChecker is implemented by services that report whether they are working.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// ReadinessChecker is implemented by services that report whether they can take traffic.
+type ReadinessChecker interface {
+	Ready(ctx context.Context) error
+}
+
+// CheckKind identifies the method a check called.
+type CheckKind string
+
+const (
+	// HealthCheckKind checks call HealthCheck.
+	HealthCheckKind CheckKind = "health"
+	// ReadinessCheckKind checks call Ready.
+	ReadinessCheckKind CheckKind = "readiness"
+)
+
+// DefaultCheckTimeout bounds each check run by Health unless CheckTimeout says otherwise.
+const DefaultCheckTimeout = 5 * time.Second
+
+// CheckResult is the outcome of one service check.
+type CheckResult struct {
+	Service  string        `json:"service"`
+	Kind     CheckKind     `json:"kind"`
+	Passed   bool          `json:"passed"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// HealthReport aggregates the checks of every service in a container.
+type HealthReport struct {
+	// Healthy is true when every health check passed.
+	Healthy bool `json:"healthy"`
+	// Ready is true when every check passed, health and readiness.
+	Ready  bool          `json:"ready"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// HealthOption configures a Health call.
+type HealthOption func(*healthSettings)
+
+type healthSettings struct {
+	timeout time.Duration
+	// kinds limits the checks that run; all of them run when it is empty.
+	kinds []CheckKind
+}
+
+// CheckTimeout bounds each check. A check still running when it expires fails with
+// context.DeadlineExceeded; it is not waited for.
+func CheckTimeout(timeout time.Duration) HealthOption {
+	return func(settings *healthSettings) {
+		if timeout > 0 {
+			settings.timeout = timeout
+		}
+	}
+}
+
+// CheckKinds limits Health to the checks of the given kinds, so a liveness probe does not
+// wait on readiness checks. Ready then only reflects the checks that ran.
+func CheckKinds(kinds ...CheckKind) HealthOption {
+	return func(settings *healthSettings) {
+		settings.kinds = kinds
+	}
+}
+
+// includes reports whether checks of the kind run.
+func (hs *healthSettings) includes(kind CheckKind) bool {
+	if len(hs.kinds) == 0 {
+		return true
+	}
+	for _, included := range hs.kinds {
+		if included == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceCheck is a check discovered on a registered service.
+type serviceCheck struct {
+	service string
+	kind    CheckKind
+	run     func(ctx context.Context) error
+}
+
+// Health runs the HealthCheck and Ready methods of the services visible from the container
+// concurrently, each with its own timeout, and returns a report sorted by kind and service.
+// Checks that panic fail with the panic value. Pooled services are not checked.
+func Health(ctx context.Context, serviceContainer ServiceContainer, options ...HealthOption) HealthReport {
+	settings := &healthSettings{timeout: DefaultCheckTimeout}
+	for _, option := range options {
+		option(settings)
+	}
+
+	var checks []serviceCheck
+	for _, check := range discoverChecks(serviceContainer) {
+		if settings.includes(check.kind) {
+			checks = append(checks, check)
+		}
+	}
+	results := make([]CheckResult, len(checks))
+	var waitGroup sync.WaitGroup
+	for index, check := range checks {
+		waitGroup.Add(1)
+		go func(index int, check serviceCheck) {
+			defer waitGroup.Done()
+			results[index] = runCheck(ctx, check, settings.timeout)
+		}(index, check)
+	}
+	waitGroup.Wait()
+
+	report := HealthReport{Healthy: true, Ready: true, Checks: results}
+	logger := loggerOf(serviceContainer)
+	for _, result := range results {
+		if result.Passed {
+			continue
+		}
+		logger.Warn("service check failed", slog.String("service", result.Service), slog.String("kind", string(result.Kind)), slog.String("error", result.Error))
+		report.Ready = false
+		if result.Kind == HealthCheckKind {
+			report.Healthy = false
+		}
+	}
+	sort.Slice(report.Checks, func(i, j int) bool {
+		if report.Checks[i].Kind != report.Checks[j].Kind {
+			return report.Checks[i].Kind < report.Checks[j].Kind
+		}
+		return report.Checks[i].Service < report.Checks[j].Service
+	})
+	return report
+}
+
+// discoverChecks lists the checks of the services visible from the container.
+func discoverChecks(serviceContainer ServiceContainer) []serviceCheck {
+	var entryList []*serviceEntry
+	if registry, ok := registryOf(serviceContainer); ok {
+		entryList = registry.visibleEntries()
+	} else {
+		entryList = entriesOf(serviceContainer)
+	}
+
+	var checks []serviceCheck
+	for _, entry := range entryList {
+		if entry.lifetime == Pooled {
+			continue
+		}
+		serviceInstance := unwrapService(entry.instance)
+		if serviceInstance == nil {
+			continue
+		}
+		service := reflect.TypeOf(serviceInstance).String()
+		if checker, ok := serviceInstance.(HealthChecker); ok {
+			checks = append(checks, serviceCheck{service: service, kind: HealthCheckKind, run: checker.HealthCheck})
+		}
+		if checker, ok := serviceInstance.(ReadinessChecker); ok {
+			checks = append(checks, serviceCheck{service: service, kind: ReadinessCheckKind, run: checker.Ready})
+		}
+	}
+	return checks
+}
+
+// runCheck runs the check with a timeout, without waiting for it past the deadline.
+func runCheck(ctx context.Context, check serviceCheck, timeout time.Duration) CheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startedAt := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- fmt.Errorf("check panicked: %v", recovered)
+			}
+		}()
+		done <- check.run(checkCtx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-checkCtx.Done():
+		err = checkCtx.Err()
+	}
+
+	result := CheckResult{Service: check.service, Kind: check.kind, Passed: err == nil, Duration: time.Since(startedAt)}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
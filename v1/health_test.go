@@ -0,0 +1,104 @@
+package sioc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type healthyDatabase struct{}
+
+func (healthyDatabase) HealthCheck(context.Context) error { return nil }
+func (healthyDatabase) Ready(context.Context) error       { return nil }
+
+type warmingCache struct{}
+
+func (*warmingCache) Ready(context.Context) error { return errors.New("cache is warming up") }
+
+type hangingQueue struct{}
+
+func (*hangingQueue) HealthCheck(ctx context.Context) error {
+	<-ctx.Done()
+	time.Sleep(time.Second)
+	return nil
+}
+
+type panickingClient struct{}
+
+func (*panickingClient) HealthCheck(context.Context) error { panic("client exploded") }
+
+// TestHealthAggregatesChecks tests that health and readiness checks are discovered and reported
+func TestHealthAggregatesChecks(t *testing.T) {
+	container := NewContainer()
+	Inject(&healthyDatabase{}, container)
+	Inject(&warmingCache{}, container)
+	Inject(&TestStruct{}, container)
+
+	report := Health(context.Background(), container)
+	if !report.Healthy || report.Ready {
+		t.Errorf("Expected healthy but not ready, got %+v", report)
+	}
+	if len(report.Checks) != 3 {
+		t.Fatalf("Expected 3 checks, got %+v", report.Checks)
+	}
+	failed := report.Checks[2]
+	if failed.Kind != ReadinessCheckKind || failed.Service != "*sioc.warmingCache" || failed.Passed || failed.Error != "cache is warming up" {
+		t.Errorf("Expected the cache readiness failure last, got %+v", failed)
+	}
+}
+
+// TestHealthCheckKinds tests that CheckKinds limits the checks that run
+func TestHealthCheckKinds(t *testing.T) {
+	container := NewContainer()
+	Inject(&healthyDatabase{}, container)
+	Inject(&warmingCache{}, container)
+
+	report := Health(context.Background(), container, CheckKinds(HealthCheckKind))
+	if !report.Healthy || !report.Ready || len(report.Checks) != 1 || report.Checks[0].Kind != HealthCheckKind {
+		t.Errorf("Expected only the health check to run, got %+v", report)
+	}
+}
+
+// TestHealthTimesOutChecks tests that slow checks fail at the timeout without being waited for
+func TestHealthTimesOutChecks(t *testing.T) {
+	container := NewContainer()
+	Inject(&hangingQueue{}, container)
+	Inject(&panickingClient{}, container)
+
+	startedAt := time.Now()
+	report := Health(context.Background(), container, CheckTimeout(20*time.Millisecond))
+	if elapsed := time.Since(startedAt); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected Health to return at the timeout, took %v", elapsed)
+	}
+	if report.Healthy {
+		t.Fatal("Expected an unhealthy report")
+	}
+	for _, check := range report.Checks {
+		switch check.Service {
+		case "*sioc.hangingQueue":
+			if check.Error != context.DeadlineExceeded.Error() {
+				t.Errorf("Expected a deadline error, got %q", check.Error)
+			}
+		case "*sioc.panickingClient":
+			if check.Error != "check panicked: client exploded" {
+				t.Errorf("Expected the panic to be reported, got %q", check.Error)
+			}
+		}
+	}
+}
+
+// TestHealthIncludesParentServices tests that scopes report the checks of their parent's services
+func TestHealthIncludesParentServices(t *testing.T) {
+	root := NewContainer()
+	Inject(&healthyDatabase{}, root)
+	scope := NewScope(root)
+	Inject(&warmingCache{}, scope)
+
+	if report := Health(context.Background(), scope); len(report.Checks) != 3 {
+		t.Errorf("Expected the scope to report 3 checks, got %+v", report.Checks)
+	}
+	if report := Health(context.Background(), root); !report.Ready {
+		t.Errorf("Expected the parent to be ready, got %+v", report)
+	}
+}
@@ -0,0 +1,109 @@
+package sioc
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Hooks receives lifecycle events from a container. Implementations must be safe
+// for concurrent use, since resolution may happen from several goroutines.
+type Hooks interface {
+	OnRegister(serviceType reflect.Type)
+	OnResolve(serviceType reflect.Type, cached bool)
+	OnInitStart(serviceType reflect.Type)
+	OnInitDone(serviceType reflect.Type, duration time.Duration)
+	OnError(serviceType reflect.Type, err error)
+}
+
+// NopHooks implements Hooks with no-op methods. Embed it to implement only the events you need.
+type NopHooks struct{}
+
+func (NopHooks) OnRegister(reflect.Type)                {}
+func (NopHooks) OnResolve(reflect.Type, bool)           {}
+func (NopHooks) OnInitStart(reflect.Type)               {}
+func (NopHooks) OnInitDone(reflect.Type, time.Duration) {}
+func (NopHooks) OnError(reflect.Type, error)            {}
+
+// HookEventKind identifies the lifecycle event recorded by a HookRecorder.
+type HookEventKind string
+
+const (
+	HookRegister  HookEventKind = "register"
+	HookResolve   HookEventKind = "resolve"
+	HookInitStart HookEventKind = "init_start"
+	HookInitDone  HookEventKind = "init_done"
+	HookError     HookEventKind = "error"
+)
+
+// HookEvent is a single lifecycle event recorded by a HookRecorder.
+type HookEvent struct {
+	Kind        HookEventKind
+	ServiceType reflect.Type
+	Cached      bool
+	Duration    time.Duration
+	Err         error
+}
+
+// HookRecorder is a Hooks implementation that keeps every event in memory, in order.
+// It is meant for tests and as a reference for custom implementations.
+type HookRecorder struct {
+	mutex  sync.Mutex
+	events []HookEvent
+}
+
+// NewHookRecorder creates an empty HookRecorder.
+func NewHookRecorder() *HookRecorder {
+	return &HookRecorder{}
+}
+
+func (hr *HookRecorder) OnRegister(serviceType reflect.Type) {
+	hr.record(HookEvent{Kind: HookRegister, ServiceType: serviceType})
+}
+
+func (hr *HookRecorder) OnResolve(serviceType reflect.Type, cached bool) {
+	hr.record(HookEvent{Kind: HookResolve, ServiceType: serviceType, Cached: cached})
+}
+
+func (hr *HookRecorder) OnInitStart(serviceType reflect.Type) {
+	hr.record(HookEvent{Kind: HookInitStart, ServiceType: serviceType})
+}
+
+func (hr *HookRecorder) OnInitDone(serviceType reflect.Type, duration time.Duration) {
+	hr.record(HookEvent{Kind: HookInitDone, ServiceType: serviceType, Duration: duration})
+}
+
+func (hr *HookRecorder) OnError(serviceType reflect.Type, err error) {
+	hr.record(HookEvent{Kind: HookError, ServiceType: serviceType, Err: err})
+}
+
+// Events returns a copy of the recorded events.
+func (hr *HookRecorder) Events() []HookEvent {
+	hr.mutex.Lock()
+	defer hr.mutex.Unlock()
+	return append([]HookEvent(nil), hr.events...)
+}
+
+// EventsOf returns a copy of the recorded events of the given kind.
+func (hr *HookRecorder) EventsOf(kind HookEventKind) []HookEvent {
+	var filtered []HookEvent
+	for _, event := range hr.Events() {
+		if event.Kind == kind {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+// Reset discards the recorded events.
+func (hr *HookRecorder) Reset() {
+	hr.mutex.Lock()
+	defer hr.mutex.Unlock()
+	hr.events = nil
+}
+
+func (hr *HookRecorder) record(event HookEvent) {
+	hr.mutex.Lock()
+	defer hr.mutex.Unlock()
+	hr.events = append(hr.events, event)
+}
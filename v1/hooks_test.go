@@ -0,0 +1,92 @@
+package sioc
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestHookRecorderReceivesLifecycleEvents tests that container operations are reported to hooks
+func TestHookRecorderReceivesLifecycleEvents(t *testing.T) {
+	recorder := NewHookRecorder()
+	container := NewContainer(WithHooks(recorder))
+
+	Inject(&TestStruct{}, container)
+	Inject(&TestStructWithDependency{}, container)
+	Init(container)
+	Get[*TestStruct](container)
+	Get[*TestStruct](container)
+
+	if registered := recorder.EventsOf(HookRegister); len(registered) != 2 {
+		t.Errorf("Expected 2 register events, got %d", len(registered))
+	}
+
+	starts := recorder.EventsOf(HookInitStart)
+	dones := recorder.EventsOf(HookInitDone)
+	if len(starts) != 2 || len(dones) != 2 {
+		t.Fatalf("Expected 2 init start and done events, got %d and %d", len(starts), len(dones))
+	}
+	for _, done := range dones {
+		if done.Duration < 0 {
+			t.Errorf("Expected a non-negative init duration, got %v", done.Duration)
+		}
+	}
+
+	resolved := recorder.EventsOf(HookResolve)
+	if len(resolved) != 2 {
+		t.Fatalf("Expected 2 resolve events, got %d", len(resolved))
+	}
+	expectedType := reflect.TypeOf(&TestStruct{})
+	if resolved[0].ServiceType != expectedType || resolved[0].Cached {
+		t.Errorf("Expected first resolution of %v to be uncached, got %+v", expectedType, resolved[0])
+	}
+	if !resolved[1].Cached {
+		t.Error("Expected second resolution to be served from the cache")
+	}
+}
+
+// TestHooksReceiveErrors tests that failures are reported through OnError
+func TestHooksReceiveErrors(t *testing.T) {
+	recorder := NewHookRecorder()
+	container := NewContainer(WithHooks(recorder))
+
+	Invoke(container, func(_ *TestService) {})
+
+	failures := recorder.EventsOf(HookError)
+	if len(failures) != 1 {
+		t.Fatalf("Expected 1 error event, got %d", len(failures))
+	}
+	if !errors.Is(failures[0].Err, ErrDependencyNotFound) {
+		t.Errorf("Expected ErrDependencyNotFound, got %v", failures[0].Err)
+	}
+}
+
+type registerCounter struct {
+	NopHooks
+	registered int
+}
+
+func (rc *registerCounter) OnRegister(reflect.Type) {
+	rc.registered++
+}
+
+// TestWithHooksMultiple tests that several hooks can be attached, including partial implementations
+func TestWithHooksMultiple(t *testing.T) {
+	recorder := NewHookRecorder()
+	counter := &registerCounter{}
+	container := NewContainer(WithHooks(recorder), WithHooks(counter, nil))
+
+	Inject(&TestStruct{}, container)
+
+	if counter.registered != 1 {
+		t.Errorf("Expected counter hook to see 1 registration, got %d", counter.registered)
+	}
+	if len(recorder.Events()) != 1 {
+		t.Errorf("Expected recorder to see 1 event, got %d", len(recorder.Events()))
+	}
+
+	recorder.Reset()
+	if len(recorder.Events()) != 0 {
+		t.Error("Reset should discard recorded events")
+	}
+}
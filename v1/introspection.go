@@ -0,0 +1,60 @@
+package sioc
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+// ServiceInfo describes a registered service and its lifecycle state.
+type ServiceInfo struct {
+	Key          string        `json:"key"`
+	Type         string        `json:"type"`
+	Lifetime     Lifetime      `json:"lifetime"`
+	HasInit      bool          `json:"hasInit"`
+	Initialized  bool          `json:"initialized"`
+	InitDuration time.Duration `json:"initDuration"`
+	Dependencies []string      `json:"dependencies"`
+	// Pool reports the pool usage of Pooled services.
+	Pool *PoolStats `json:"pool,omitempty"`
+}
+
+// Describe returns a snapshot of every service registered in the container, sorted by key.
+// Dependencies list the concrete types that were passed to the service's Init method.
+func Describe(serviceContainer ServiceContainer) []ServiceInfo {
+	var services []ServiceInfo
+	for _, entry := range entriesOf(serviceContainer) {
+		services = append(services, entry.describe())
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Key < services[j].Key
+	})
+	return services
+}
+
+// describe builds the ServiceInfo for the entry.
+func (se *serviceEntry) describe() ServiceInfo {
+	serviceInstance := se.instance
+	if wrapper, ok := serviceInstance.(ServiceWrapper[any]); ok {
+		serviceInstance = wrapper.GetService()
+	}
+
+	info := ServiceInfo{Key: se.key, Lifetime: se.lifetime, Dependencies: []string{}}
+	if pooled, ok := se.instance.(*pooledService); ok {
+		stats := pooled.stats()
+		info.Pool = &stats
+	}
+	if serviceInstance != nil {
+		info.Type = reflect.TypeOf(serviceInstance).String()
+		info.HasInit = reflect.ValueOf(serviceInstance).MethodByName("Init").IsValid()
+	}
+
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	info.Initialized = se.initialized
+	info.InitDuration = se.initDuration
+	for _, dependency := range se.dependencies {
+		info.Dependencies = append(info.Dependencies, dependency.String())
+	}
+	return info
+}
@@ -0,0 +1,64 @@
+package sioc
+
+import (
+	"testing"
+)
+
+// TestDescribeReportsInitState tests that Describe reports types, init status and dependency edges
+func TestDescribeReportsInitState(t *testing.T) {
+	container := NewContainer()
+	Inject(&TestStruct{}, container)
+	Inject(&TestStructWithDependency{}, container)
+	Inject(&TestService{}, container)
+
+	for _, info := range Describe(container) {
+		if info.Initialized {
+			t.Errorf("Service %s should not be initialized before Init", info.Key)
+		}
+	}
+
+	Init(container)
+
+	services := Describe(container)
+	if len(services) != 3 {
+		t.Fatalf("Expected 3 services, got %d", len(services))
+	}
+	if services[0].Key > services[1].Key || services[1].Key > services[2].Key {
+		t.Errorf("Expected services sorted by key, got %v", services)
+	}
+
+	byType := map[string]ServiceInfo{}
+	for _, info := range services {
+		if info.Lifetime != Singleton {
+			t.Errorf("Expected singleton lifetime for %s, got %s", info.Key, info.Lifetime)
+		}
+		byType[info.Type] = info
+	}
+
+	dependent := byType["*sioc.TestStructWithDependency"]
+	if !dependent.HasInit || !dependent.Initialized {
+		t.Errorf("Expected dependent service to be initialized, got %+v", dependent)
+	}
+	if len(dependent.Dependencies) != 1 || dependent.Dependencies[0] != "*sioc.TestStruct" {
+		t.Errorf("Expected dependency edge to *sioc.TestStruct, got %v", dependent.Dependencies)
+	}
+
+	withoutInit := byType["*sioc.TestService"]
+	if withoutInit.HasInit || withoutInit.Initialized {
+		t.Errorf("Service without Init should not be reported as initialized, got %+v", withoutInit)
+	}
+}
+
+// TestInitSkipsInitializedServices tests that a second Init does not call Init again
+func TestInitSkipsInitializedServices(t *testing.T) {
+	recorder := NewHookRecorder()
+	container := NewContainer(WithHooks(recorder))
+	Inject(&TestStruct{}, container)
+
+	Init(container)
+	Init(container)
+
+	if starts := recorder.EventsOf(HookInitStart); len(starts) != 1 {
+		t.Errorf("Expected Init to run once, ran %d times", len(starts))
+	}
+}
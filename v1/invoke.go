@@ -0,0 +1,75 @@
+package sioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Invoke calls function with every parameter resolved from the container.
+// Parameters are matched the same way as Init methods. If the last result of
+// function is an error, it is returned to the caller, and a panic in function is
+// returned as a *PanicError. Pooled arguments go back to their pool when function returns.
+func Invoke(serviceContainer ServiceContainer, function any) error {
+	functionValue := reflect.ValueOf(function)
+	if functionValue.Kind() != reflect.Func || functionValue.IsNil() {
+		return fmt.Errorf("sioc: cannot invoke %T: not a function", function)
+	}
+
+	functionType := functionValue.Type()
+	if functionType.IsVariadic() {
+		return fmt.Errorf("sioc: cannot invoke %v: variadic functions are not supported", functionType)
+	}
+
+	arguments, leases, err := resolveArguments(functionType, buildDependencyMap(serviceContainer))
+	var provisionErr error
+	// Pending providers may supply the arguments; resolve again after each one that provides.
+	for errors.Is(err, ErrDependencyNotFound) {
+		var provided bool
+		provided, provisionErr = providePending(serviceContainer, false)
+		if !provided {
+			break
+		}
+		arguments, leases, err = resolveArguments(functionType, buildDependencyMap(serviceContainer))
+	}
+	// The dependency is missing because its provider failed
+	if errors.Is(err, ErrDependencyNotFound) && provisionErr != nil {
+		err = provisionErr
+	}
+	if err != nil {
+		err = fmt.Errorf("sioc: cannot invoke %v: %w", functionType, withPath(err, functionType))
+		eventsOf(serviceContainer).failed("invoke failed", functionType, err)
+		return err
+	}
+
+	// The function must not keep its pooled arguments, so they go back once it returns.
+	defer releaseLeases(leases)
+	if err := callInvoked(serviceContainer, functionValue, arguments); err != nil {
+		var panicErr *PanicError
+		if errors.As(err, &panicErr) {
+			eventsOf(serviceContainer).failed("invoke failed", functionType, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// callInvoked calls the function and returns its error result, or the PanicError of a panic.
+func callInvoked(serviceContainer ServiceContainer, functionValue reflect.Value, arguments []reflect.Value) (err error) {
+	defer recoverPanic(repanics(serviceContainer), functionValue.Type(), invokeStage, &err)
+	return returnedError(functionValue.Type(), functionValue.Call(arguments))
+}
+
+// returnedError returns the error a function returned as its last result, if any.
+func returnedError(functionType reflect.Type, results []reflect.Value) error {
+	if len(results) == 0 {
+		return nil
+	}
+	lastResult := results[len(results)-1]
+	if functionType.Out(len(results)-1) != errorType || lastResult.IsNil() {
+		return nil
+	}
+	return lastResult.Interface().(error)
+}
@@ -0,0 +1,93 @@
+package sioc
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestInvokeResolvesParameters tests that every parameter is injected from the container
+func TestInvokeResolvesParameters(t *testing.T) {
+	container := NewContainer()
+	Inject(&TestStruct{Value: "dependency"}, container)
+	Inject(&TestService{Name: "service"}, container)
+
+	var gotStruct *TestStruct
+	var gotService *TestService
+	err := Invoke(container, func(testStruct *TestStruct, testService *TestService) {
+		gotStruct = testStruct
+		gotService = testService
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if gotStruct == nil || gotStruct.Value != "dependency" {
+		t.Errorf("Expected *TestStruct to be injected, got %+v", gotStruct)
+	}
+	if gotService == nil || gotService.Name != "service" {
+		t.Errorf("Expected *TestService to be injected, got %+v", gotService)
+	}
+}
+
+// TestInvokeResolvesInterfaces tests that interface parameters are matched by implementation
+func TestInvokeResolvesInterfaces(t *testing.T) {
+	container := NewContainer()
+	Inject(&TestStruct{Value: "interface"}, container)
+
+	var got TestInterface
+	if err := Invoke(container, func(value TestInterface) { got = value }); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got == nil || got.GetValue() != "interface" {
+		t.Errorf("Expected TestInterface to be injected, got %v", got)
+	}
+}
+
+// TestInvokeReturnsFunctionError tests that the error returned by the function is propagated
+func TestInvokeReturnsFunctionError(t *testing.T) {
+	container := NewContainer()
+	Inject(&TestStruct{}, container)
+	expected := errors.New("migration failed")
+
+	err := Invoke(container, func(_ *TestStruct) (int, error) { return 0, expected })
+	if !errors.Is(err, expected) {
+		t.Errorf("Expected %v, got %v", expected, err)
+	}
+
+	if err := Invoke(container, func(_ *TestStruct) error { return nil }); err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+}
+
+// TestInvokeMissingDependency tests that unresolved parameters are reported without calling the function
+func TestInvokeMissingDependency(t *testing.T) {
+	container := NewContainer()
+	called := false
+
+	err := Invoke(container, func(_ *TestService) { called = true })
+	if !errors.Is(err, ErrDependencyNotFound) {
+		t.Errorf("Expected ErrDependencyNotFound, got %v", err)
+	}
+	if called {
+		t.Error("Function should not be called when a dependency is missing")
+	}
+}
+
+// TestInvokeRejectsNonFunctions tests that non-function values are rejected
+func TestInvokeRejectsNonFunctions(t *testing.T) {
+	container := NewContainer()
+
+	if err := Invoke(container, "not a function"); err == nil {
+		t.Error("Expected error when invoking a non-function")
+	}
+
+	var nilFunction func()
+	if err := Invoke(container, nilFunction); err == nil {
+		t.Error("Expected error when invoking a nil function")
+	}
+
+	if err := Invoke(container, func(_ ...*TestStruct) {}); err == nil {
+		t.Error("Expected error when invoking a variadic function")
+	}
+}
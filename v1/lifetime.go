@@ -0,0 +1,14 @@
+package sioc
+
+// Lifetime describes how long a registered service instance lives.
+type Lifetime string
+
+const (
+	// Singleton services are created once and shared by every resolution.
+	Singleton Lifetime = "singleton"
+	// Scoped services are registered in a Scope and disposed when the scope is closed.
+	Scoped Lifetime = "scoped"
+	// Pooled services are handed out from a pool by Get and go back to it with Release,
+	// or when the scope that resolved them is closed.
+	Pooled Lifetime = "pooled"
+)
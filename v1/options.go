@@ -0,0 +1,94 @@
+package sioc
+
+import (
+	"log/slog"
+	"time"
+)
+
+// ContainerOption configures a service container created by NewContainer.
+type ContainerOption func(*serviceRegistry)
+
+// WithLogHandler sends the container's structured events (registration, resolution,
+// initialization and failures) to the given slog.Handler. Containers are silent by default.
+func WithLogHandler(handler slog.Handler) ContainerOption {
+	return func(sr *serviceRegistry) {
+		if handler != nil {
+			sr.events.logger = slog.New(handler)
+		}
+	}
+}
+
+// WithHooks attaches lifecycle hooks to the container. It may be given several times;
+// hooks are called in the order they were attached.
+func WithHooks(hooks ...Hooks) ContainerOption {
+	return func(sr *serviceRegistry) {
+		for _, hook := range hooks {
+			if hook != nil {
+				sr.events.hooks = append(sr.events.hooks, hook)
+			}
+		}
+	}
+}
+
+// WithRepanic lets panics in Init methods, providers, pooled constructors and invoked
+// functions unwind with their original stack, instead of converting them into a PanicError.
+// It is meant for development, where a debugger or the runtime's trace is more useful.
+func WithRepanic() ContainerOption {
+	return func(sr *serviceRegistry) {
+		sr.repanic = true
+	}
+}
+
+// WithProfiles sets the container's active profiles, overriding the SIOC_PROFILES
+// environment variable.
+func WithProfiles(profiles ...string) ContainerOption {
+	return func(sr *serviceRegistry) {
+		sr.profiles = normalizeProfiles(profiles)
+	}
+}
+
+// InjectOption configures a single registration made with Inject or InjectAs.
+type InjectOption func(*injectSettings)
+
+// injectSettings holds the options of a single registration.
+type injectSettings struct {
+	profiles    []string
+	initialized bool
+	retry       retryPolicy
+}
+
+func newInjectSettings(options []InjectOption) *injectSettings {
+	settings := &injectSettings{retry: retryPolicy{attempts: 1}}
+	for _, option := range options {
+		option(settings)
+	}
+	return settings
+}
+
+// Profile restricts the registration to containers where at least one of the profiles is
+// active. A profile prefixed with "!" matches when that profile is not active.
+func Profile(profiles ...string) InjectOption {
+	return func(settings *injectSettings) {
+		settings.profiles = append(settings.profiles, normalizeProfiles(profiles)...)
+	}
+}
+
+// Initialized marks the service as already initialized, so Init will not call its Init
+// method. It is used by generated wiring code that calls Init itself.
+func Initialized() InjectOption {
+	return func(settings *injectSettings) {
+		settings.initialized = true
+	}
+}
+
+// WithRetry calls the service's Init method up to attempts times while it returns an error,
+// waiting backoff before the second attempt and twice as long after every further failure, up
+// to a minute. Init gives up with the last error. Every attempt is reported to the logger and
+// hooks. Retries apply only to Init; a provider initialized by a lookup gets a single attempt.
+func WithRetry(attempts int, backoff time.Duration) InjectOption {
+	return func(settings *injectSettings) {
+		if attempts > 0 {
+			settings.retry = retryPolicy{attempts: attempts, backoff: backoff}
+		}
+	}
+}
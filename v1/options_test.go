@@ -0,0 +1,100 @@
+package sioc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// decodeLogRecords parses the JSON lines written by a slog.JSONHandler
+func decodeLogRecords(t *testing.T, buffer *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	decoder := json.NewDecoder(buffer)
+	for decoder.More() {
+		record := map[string]any{}
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatalf("Invalid log record: %v", err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// TestWithLogHandlerEmitsEvents tests that registration, resolution and init are logged
+func TestWithLogHandlerEmitsEvents(t *testing.T) {
+	var buffer bytes.Buffer
+	handler := slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug})
+	container := NewContainer(WithLogHandler(handler))
+
+	Inject(&TestStruct{}, container)
+	Inject(&TestStructWithDependency{}, container)
+	Init(container)
+	Get[*TestStruct](container)
+
+	messages := map[string]map[string]any{}
+	for _, record := range decodeLogRecords(t, &buffer) {
+		messages[record["msg"].(string)] = record
+	}
+
+	for _, expected := range []string{"service registered", "init started", "service init started", "service init finished", "init finished", "service resolved"} {
+		if _, found := messages[expected]; !found {
+			t.Errorf("Expected %q event to be logged", expected)
+		}
+	}
+
+	if _, found := messages["service init finished"]["duration"]; !found {
+		t.Error("Expected init finished event to carry a duration")
+	}
+	if service := messages["service resolved"]["service"]; service != "*sioc.TestStruct" {
+		t.Errorf("Expected resolved service '*sioc.TestStruct', got %v", service)
+	}
+}
+
+// TestWithLogHandlerReportsFailures tests that failures are logged at error level
+func TestWithLogHandlerReportsFailures(t *testing.T) {
+	var buffer bytes.Buffer
+	container := NewContainer(WithLogHandler(slog.NewJSONHandler(&buffer, nil)))
+
+	if err := Invoke(container, func(_ *TestService) {}); err == nil {
+		t.Fatal("Expected Invoke to fail")
+	}
+
+	records := decodeLogRecords(t, &buffer)
+	if len(records) != 1 {
+		t.Fatalf("Expected only the failure to be logged at info level, got %d records", len(records))
+	}
+	if records[0]["level"] != "ERROR" || records[0]["msg"] != "invoke failed" {
+		t.Errorf("Expected an ERROR 'invoke failed' record, got %v", records[0])
+	}
+}
+
+// TestNewContainerIsSilentByDefault tests that containers without a handler discard events
+func TestNewContainerIsSilentByDefault(t *testing.T) {
+	container := NewContainer()
+	if loggerOf(container).Enabled(context.Background(), slog.LevelError) {
+		t.Error("Default container logger should discard every level")
+	}
+
+	if loggerOf(NewContainer(WithLogHandler(nil))).Enabled(context.Background(), slog.LevelError) {
+		t.Error("A nil handler should keep the container silent")
+	}
+}
+
+// TestInitializedOptionSkipsInit tests that services registered as initialized are not initialized again
+func TestInitializedOptionSkipsInit(t *testing.T) {
+	container := NewContainer()
+	service := &TestStruct{}
+	Inject(service, container, Initialized())
+
+	Init(container)
+
+	if service.initialized {
+		t.Error("Init should not be called for services registered as initialized")
+	}
+	if info := Describe(container)[0]; !info.Initialized {
+		t.Error("Expected the service to be reported as initialized")
+	}
+}
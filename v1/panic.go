@@ -0,0 +1,105 @@
+package sioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strings"
+)
+
+// Stages of the code called by the container, as reported by PanicError.
+const (
+	initStage        = "Init"
+	constructorStage = "constructor"
+	providerStage    = "provider"
+	invokeStage      = "invoked function"
+	tenantStage      = "tenant module"
+)
+
+// PanicError is the error a panic is converted into when it happens in code the container
+// calls: Init methods, ServiceProvider.ProvideService, pooled service constructors, functions
+// given to Invoke and tenant modules. Where the calling API has no error result, as with Get,
+// the PanicError is panicked instead of the original value; InitE returns it wrapped.
+type PanicError struct {
+	// ServiceType is the service, or invoked function, whose code panicked.
+	ServiceType reflect.Type
+	// Stage names the code that panicked: "Init", "constructor", "provider", "invoked function"
+	// or "tenant module".
+	Stage string
+	// Path is the chain of services being resolved when the panic happened, from the
+	// outermost one to ServiceType.
+	Path []reflect.Type
+	// Value is the value given to panic.
+	Value any
+	// Stack is the stack trace of the goroutine at the panic.
+	Stack []byte
+}
+
+func (pe *PanicError) Error() string {
+	message := fmt.Sprintf("sioc: panic in %s of %v: %v", pe.Stage, pe.ServiceType, pe.Value)
+	if len(pe.Path) > 1 {
+		path := make([]string, len(pe.Path))
+		for index, serviceType := range pe.Path {
+			path[index] = serviceType.String()
+		}
+		message += " (path: " + strings.Join(path, " -> ") + ")"
+	}
+	return message
+}
+
+// Unwrap returns the panic value when it is an error.
+func (pe *PanicError) Unwrap() error {
+	err, _ := pe.Value.(error)
+	return err
+}
+
+// recoverPanic converts a panic into a *PanicError stored in err. It must be deferred
+// directly. Containers created with WithRepanic leave the panic alone, so it unwinds with
+// its original stack.
+func recoverPanic(repanic bool, serviceType reflect.Type, stage string, err *error) {
+	if repanic {
+		return
+	}
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	// A PanicError panicked by nested resolution, such as a Get in Init, gains this service.
+	if inner, ok := recovered.(*PanicError); ok {
+		*err = withPath(inner, serviceType)
+		return
+	}
+	*err = &PanicError{
+		ServiceType: serviceType,
+		Stage:       stage,
+		Path:        []reflect.Type{serviceType},
+		Value:       recovered,
+		Stack:       debug.Stack(),
+	}
+}
+
+// withPath adds the service to the front of the dependency path of a PanicError.
+// Other errors are returned unchanged.
+func withPath(err error, serviceType reflect.Type) error {
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		panicErr.Path = append([]reflect.Type{serviceType}, panicErr.Path...)
+	}
+	return err
+}
+
+// panicStack returns the stack of a PanicError, to print it beside a fatal error.
+func panicStack(err error) string {
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		return "\n" + string(panicErr.Stack)
+	}
+	return ""
+}
+
+// repanics reports whether the container was created with WithRepanic.
+func repanics(serviceContainer ServiceContainer) bool {
+	registry, ok := registryOf(serviceContainer)
+	return ok && registry.repanic
+}
@@ -0,0 +1,164 @@
+package sioc
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type explodingService struct {
+	calls int
+}
+
+func (es *explodingService) Init() {
+	es.calls++
+	panic("exploding service")
+}
+
+type fragileWorker struct{}
+
+type workerConsumer struct {
+	worker *fragileWorker
+}
+
+func (wc *workerConsumer) Init(worker *fragileWorker) {
+	wc.worker = worker
+}
+
+type explodingProvider struct{}
+
+func (*explodingProvider) Init() {}
+
+func (*explodingProvider) ProvideService() any {
+	panic(errors.New("provider exploded"))
+}
+
+// initializeService runs the Init method of the registered service the way Init does.
+func initializeService(t *testing.T, container ServiceContainer, service any) error {
+	t.Helper()
+	for _, entry := range entriesOf(container) {
+		if unwrapService(entry.instance) == service {
+			_, err := initializeEntry(container, entry, buildDependencyMap(container), true)
+			return err
+		}
+	}
+	t.Fatalf("Service %T is not registered", service)
+	return nil
+}
+
+// TestInitPanicBecomesError tests that a panic in Init is returned with the service and stack
+func TestInitPanicBecomesError(t *testing.T) {
+	container := NewContainer()
+	service := &explodingService{}
+	Inject(service, container, WithRetry(3, time.Millisecond))
+
+	err := initializeService(t, container, service)
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected a PanicError, got %v", err)
+	}
+	if panicErr.ServiceType != reflect.TypeOf(service) || panicErr.Stage != "Init" || panicErr.Value != "exploding service" {
+		t.Errorf("Expected the service, stage and value to be reported, got %+v", panicErr)
+	}
+	if !strings.Contains(string(panicErr.Stack), "explodingService).Init") {
+		t.Errorf("Expected the stack to show the panicking method, got:\n%s", panicErr.Stack)
+	}
+	if service.calls != 1 {
+		t.Errorf("Expected a panicking Init not to be retried, got %d calls", service.calls)
+	}
+}
+
+// TestInitEReturnsPanicError tests that InitE returns a panic in Init instead of ending the process
+func TestInitEReturnsPanicError(t *testing.T) {
+	container := NewContainer()
+	Inject(&explodingService{}, container)
+
+	err := InitE(container)
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Stage != "Init" {
+		t.Fatalf("Expected the PanicError to be returned, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "init of *sioc.explodingService") {
+		t.Errorf("Expected the error to name the service, got %v", err)
+	}
+}
+
+// TestConstructorPanicReportsPath tests that a panic building a dependency names the dependency path
+func TestConstructorPanicReportsPath(t *testing.T) {
+	container := NewContainer()
+	built := 0
+	InjectPooled(func() *fragileWorker {
+		built++
+		if built > 1 {
+			panic("worker exploded")
+		}
+		return &fragileWorker{}
+	}, container)
+	consumer := &workerConsumer{}
+	Inject(consumer, container)
+
+	err := initializeService(t, container, consumer)
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Stage != "constructor" {
+		t.Fatalf("Expected a constructor PanicError, got %v", err)
+	}
+	want := "sioc: panic in constructor of *sioc.fragileWorker: worker exploded (path: *sioc.workerConsumer -> *sioc.fragileWorker)"
+	if err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+
+	defer func() {
+		if _, ok := recover().(*PanicError); !ok {
+			t.Error("Expected Get to panic with a PanicError")
+		}
+	}()
+	Get[*fragileWorker](container)
+}
+
+// TestProviderPanicBecomesError tests that a panic in ProvideService is returned as an error
+func TestProviderPanicBecomesError(t *testing.T) {
+	container := NewContainer()
+	provider := &explodingProvider{}
+	Inject(provider, container)
+
+	err := initializeService(t, container, provider)
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Stage != "provider" {
+		t.Fatalf("Expected a provider PanicError, got %v", err)
+	}
+	if panicErr.Unwrap() == nil || panicErr.Unwrap().Error() != "provider exploded" {
+		t.Errorf("Expected the panic error to be unwrapped, got %v", panicErr.Unwrap())
+	}
+}
+
+// TestInvokePanicBecomesError tests that a panic in an invoked function is returned
+func TestInvokePanicBecomesError(t *testing.T) {
+	recorder := NewHookRecorder()
+	container := NewContainer(WithHooks(recorder))
+	Inject(&TestStruct{}, container)
+
+	err := Invoke(container, func(*TestStruct) { panic("handler exploded") })
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Stage != "invoked function" {
+		t.Fatalf("Expected an invoke PanicError, got %v", err)
+	}
+	if len(recorder.EventsOf(HookError)) != 1 {
+		t.Error("Expected the panic to be reported to the hooks")
+	}
+}
+
+// TestWithRepanicKeepsOriginalPanic tests that WithRepanic lets panics through, scopes included
+func TestWithRepanicKeepsOriginalPanic(t *testing.T) {
+	container := NewScope(NewContainer(WithRepanic()))
+	Inject(&TestStruct{}, container)
+
+	defer func() {
+		if recovered := recover(); recovered != "handler exploded" {
+			t.Errorf("Expected the original panic value, got %v", recovered)
+		}
+	}()
+	Invoke(container, func(*TestStruct) { panic("handler exploded") })
+	t.Error("Expected Invoke to panic")
+}
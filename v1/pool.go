@@ -0,0 +1,166 @@
+package sioc
+
+import (
+	"log"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// PoolStats reports how a pooled service's pool is used.
+type PoolStats struct {
+	// Hits counts instances handed out from the pool.
+	Hits uint64 `json:"hits"`
+	// Misses counts instances built because the pool was empty.
+	Misses uint64 `json:"misses"`
+	// Outstanding counts instances handed out and not released yet.
+	Outstanding int64 `json:"outstanding"`
+}
+
+// resetter is implemented by pooled services that clear their state before going back to the pool.
+type resetter interface {
+	Reset()
+}
+
+// pooledService is the wrapper registered for pooled services. GetService returns a prototype
+// built at registration, used to inspect the service's type; instances come from acquire.
+type pooledService struct {
+	*serviceWrapper[any]
+	pool       sync.Pool
+	newService func() any
+	// repanic leaves panics in newService unrecovered; see WithRepanic.
+	repanic bool
+
+	hits        uint64
+	misses      uint64
+	outstanding int64
+
+	// handedOutMutex guards handedOut, the instances handed out and not released yet, so an
+	// instance is not put in the pool twice. Instances of non-comparable types are not tracked.
+	handedOutMutex sync.Mutex
+	handedOut      map[any]struct{}
+}
+
+// acquire hands out an instance from the pool, building one when it is empty.
+func (ps *pooledService) acquire() (any, error) {
+	service := ps.pool.Get()
+	if service == nil {
+		atomic.AddUint64(&ps.misses, 1)
+		var err error
+		if service, err = ps.construct(); err != nil {
+			return nil, err
+		}
+	} else {
+		atomic.AddUint64(&ps.hits, 1)
+	}
+	if reflect.TypeOf(service).Comparable() {
+		ps.handedOutMutex.Lock()
+		if ps.handedOut == nil {
+			ps.handedOut = make(map[any]struct{})
+		}
+		ps.handedOut[service] = struct{}{}
+		ps.handedOutMutex.Unlock()
+	}
+	atomic.AddInt64(&ps.outstanding, 1)
+	return service, nil
+}
+
+// construct builds an instance, converting a panic in newService into a PanicError.
+func (ps *pooledService) construct() (service any, err error) {
+	defer recoverPanic(ps.repanic, ps.serviceType, constructorStage, &err)
+	return ps.newService(), nil
+}
+
+// release resets the instance and puts it back in the pool. Instances that are not handed
+// out, such as ones released already, are ignored.
+func (ps *pooledService) release(service any) {
+	if reflect.TypeOf(service).Comparable() {
+		ps.handedOutMutex.Lock()
+		_, handedOut := ps.handedOut[service]
+		delete(ps.handedOut, service)
+		ps.handedOutMutex.Unlock()
+		if !handedOut {
+			return
+		}
+	}
+	if resettable, ok := service.(resetter); ok {
+		resettable.Reset()
+	}
+	atomic.AddInt64(&ps.outstanding, -1)
+	ps.pool.Put(service)
+}
+
+func (ps *pooledService) stats() PoolStats {
+	return PoolStats{
+		Hits:        atomic.LoadUint64(&ps.hits),
+		Misses:      atomic.LoadUint64(&ps.misses),
+		Outstanding: atomic.LoadInt64(&ps.outstanding),
+	}
+}
+
+// InjectPooled registers T with the Pooled lifetime. Get hands out instances from a
+// sync.Pool, calling newService when it is empty; Release, or closing the scope they were
+// resolved from, returns them after calling their Reset method, if they have one. Pooled
+// services are not initialized by Init: newService must return ready instances.
+func InjectPooled[T any](newService func() T, serviceContainer ServiceContainer, options ...InjectOption) {
+	serviceType := reflect.TypeOf((*T)(nil)).Elem()
+	// Services excluded by their profile are never constructed.
+	if skipsRegistration(serviceContainer, serviceType, newInjectSettings(options)) {
+		return
+	}
+	pooled := &pooledService{
+		serviceWrapper: &serviceWrapper[any]{serviceType: serviceType},
+		newService:     func() any { return newService() },
+		repanic:        repanics(serviceContainer),
+	}
+	prototype, err := pooled.construct()
+	if err != nil {
+		eventsOf(serviceContainer).failed("service construction failed", serviceType, err)
+		log.Fatalf("Constructor of %v: %v%s", serviceType, err, panicStack(err))
+	}
+	pooled.SetService(prototype)
+	registerWrapper(serviceContainer, serviceType, pooled, options)
+}
+
+// Release returns a pooled service to its pool. Other services, and instances released
+// already, are left alone, so callers may release whatever they resolved.
+func Release(serviceContainer ServiceContainer, service any) {
+	pooled, found := pooledServiceFor(serviceContainer, service)
+	if !found {
+		return
+	}
+	if scope, ok := serviceContainer.(*scopeRegistry); ok && !scope.forgetLease(service) {
+		return
+	}
+	pooled.release(service)
+}
+
+// pooledServiceFor finds the pool that hands out services of the same type as the given one.
+func pooledServiceFor(serviceContainer ServiceContainer, service any) (*pooledService, bool) {
+	registry, ok := registryOf(serviceContainer)
+	if !ok || service == nil {
+		return nil, false
+	}
+	serviceType := reflect.TypeOf(service)
+	for _, entry := range registry.visibleEntries() {
+		if pooled, ok := entry.instance.(*pooledService); ok && reflect.TypeOf(pooled.GetService()) == serviceType {
+			return pooled, true
+		}
+	}
+	return nil, false
+}
+
+// releaseLeases returns the pooled services of the leases to their pools.
+func releaseLeases(leases []poolLease) {
+	for _, lease := range leases {
+		lease.pool.release(lease.service)
+	}
+}
+
+// serviceOf returns the instance of the dependency passed to Init and Invoke parameters.
+func serviceOf(dependency ServiceWrapper[any]) (any, error) {
+	if pooled, ok := dependency.(*pooledService); ok {
+		return pooled.acquire()
+	}
+	return dependency.GetService(), nil
+}
@@ -0,0 +1,217 @@
+package sioc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type pooledBuffer struct {
+	data   []byte
+	resets int
+}
+
+func (pb *pooledBuffer) Reset() {
+	pb.data = pb.data[:0]
+	pb.resets++
+}
+
+type pooledConsumer struct {
+	buffer *pooledBuffer
+}
+
+func (pc *pooledConsumer) Init(buffer *pooledBuffer) {
+	pc.buffer = buffer
+}
+
+// TestPooledGetAndRelease tests that released instances are reset and handed out again
+func TestPooledGetAndRelease(t *testing.T) {
+	container := NewContainer()
+	built := 0
+	InjectPooled(func() *pooledBuffer {
+		built++
+		return &pooledBuffer{}
+	}, container)
+
+	first := Get[*pooledBuffer](container)
+	second := Get[*pooledBuffer](container)
+	if first == second {
+		t.Fatal("Expected outstanding instances to be distinct")
+	}
+	first.data = append(first.data, "dirty"...)
+
+	Release(container, first)
+	if first.resets != 1 || len(first.data) != 0 {
+		t.Errorf("Expected Release to reset the instance, got %+v", first)
+	}
+
+	info := Describe(container)[0]
+	if info.Lifetime != Pooled || info.Pool == nil {
+		t.Fatalf("Expected pooled service info, got %+v", info)
+	}
+	if info.Pool.Outstanding != 1 {
+		t.Errorf("Expected 1 outstanding instance, got %d", info.Pool.Outstanding)
+	}
+	// The prototype built at registration plus one miss per Get on an empty pool.
+	if info.Pool.Misses != 2 || built != 3 {
+		t.Errorf("Expected 2 misses and 3 builds, got %d and %d", info.Pool.Misses, built)
+	}
+}
+
+// TestPooledReleaseTwice tests that releasing an instance twice on a root container puts it back once
+func TestPooledReleaseTwice(t *testing.T) {
+	container := NewContainer()
+	InjectPooled(func() *pooledBuffer { return &pooledBuffer{} }, container)
+
+	buffer := Get[*pooledBuffer](container)
+	Release(container, buffer)
+	Release(container, buffer)
+	Release(container, &pooledBuffer{})
+
+	if buffer.resets != 1 {
+		t.Errorf("Expected a single reset, got %d", buffer.resets)
+	}
+	if outstanding := Describe(container)[0].Pool.Outstanding; outstanding != 0 {
+		t.Errorf("Expected no outstanding instances, got %d", outstanding)
+	}
+	if Get[*pooledBuffer](container) == Get[*pooledBuffer](container) {
+		t.Error("Expected distinct instances after a repeated Release")
+	}
+}
+
+// TestPooledHits tests that instances put back in the pool are counted as hits
+func TestPooledHits(t *testing.T) {
+	container := NewContainer()
+	InjectPooled(func() *pooledBuffer { return &pooledBuffer{} }, container)
+
+	// sync.Pool may drop items at any time, so only the totals are checked.
+	for index := 0; index < 10; index++ {
+		Release(container, Get[*pooledBuffer](container))
+	}
+	stats := Describe(container)[0].Pool
+	if stats.Hits+stats.Misses != 10 || stats.Outstanding != 0 {
+		t.Errorf("Expected 10 acquisitions and none outstanding, got %+v", stats)
+	}
+}
+
+// TestPooledScopeCloseReleases tests that closing a scope returns the instances resolved from it
+func TestPooledScopeCloseReleases(t *testing.T) {
+	root := NewContainer()
+	InjectPooled(func() *pooledBuffer { return &pooledBuffer{} }, root)
+
+	scope := NewScope(root)
+	first := Get[*pooledBuffer](scope)
+	second := Get[*pooledBuffer](scope)
+	Release(scope, second)
+	Release(scope, second)
+
+	if err := scope.Close(); err != nil {
+		t.Fatalf("Expected Close to succeed, got %v", err)
+	}
+	if first.resets != 1 || second.resets != 1 {
+		t.Errorf("Expected each instance to be reset once, got %d and %d", first.resets, second.resets)
+	}
+	if stats := Describe(root)[0].Pool; stats.Outstanding != 0 {
+		t.Errorf("Expected no outstanding instances, got %d", stats.Outstanding)
+	}
+}
+
+// TestPooledDependency tests that Init receives pooled dependencies and skips the pooled service
+func TestPooledDependency(t *testing.T) {
+	container := NewContainer()
+	InjectPooled(func() *pooledBuffer { return &pooledBuffer{} }, container)
+	consumer := &pooledConsumer{}
+	Inject(consumer, container)
+	Init(container)
+
+	if consumer.buffer == nil {
+		t.Fatal("Expected the consumer to receive a pooled buffer")
+	}
+	for _, info := range Describe(container) {
+		if info.Lifetime == Pooled && info.Pool.Outstanding != 1 {
+			t.Errorf("Expected the injected buffer to be outstanding, got %+v", info.Pool)
+		}
+	}
+}
+
+// TestReleaseIgnoresOtherServices tests that Release leaves services that are not pooled alone
+func TestReleaseIgnoresOtherServices(t *testing.T) {
+	container := NewContainer()
+	service := &TestStruct{Value: "singleton"}
+	Inject(service, container)
+
+	Release(container, service)
+	Release(container, nil)
+	if Get[*TestStruct](container) != service {
+		t.Error("Expected the singleton to stay registered")
+	}
+}
+
+// TestPoolStatsJSON tests that pool statistics are only serialized for pooled services
+func TestPoolStatsJSON(t *testing.T) {
+	container := NewContainer()
+	Inject(&TestStruct{}, container)
+	InjectPooled(func() *pooledBuffer { return &pooledBuffer{} }, container)
+
+	encoded, err := json.Marshal(Describe(container))
+	if err != nil {
+		t.Fatalf("Expected services to encode, got %v", err)
+	}
+	if count := strings.Count(string(encoded), `"pool":`); count != 1 {
+		t.Errorf("Expected one pool object, got %d in %s", count, encoded)
+	}
+}
+
+// TestInvokeReleasesPooledArguments tests that pooled arguments go back to the pool once Invoke returns
+func TestInvokeReleasesPooledArguments(t *testing.T) {
+	container := NewContainer()
+	InjectPooled(func() *pooledBuffer { return &pooledBuffer{} }, container)
+
+	// sync.Pool may drop items, so a hit is only expected over several calls.
+	for index := 0; index < 20; index++ {
+		err := Invoke(container, func(buffer *pooledBuffer) {
+			buffer.data = append(buffer.data, "request"...)
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	stats := Describe(container)[0].Pool
+	if stats.Hits == 0 || stats.Outstanding != 0 {
+		t.Errorf("Expected released arguments to be handed out again, got %+v", stats)
+	}
+}
+
+// TestScopedInitLeasesPooledArguments tests that pooled Init arguments are returned when the scope closes
+func TestScopedInitLeasesPooledArguments(t *testing.T) {
+	root := NewContainer()
+	InjectPooled(func() *pooledBuffer { return &pooledBuffer{} }, root)
+	scope := NewScope(root)
+	consumer := &pooledConsumer{}
+	Inject(consumer, scope)
+	Init(scope)
+
+	if stats := Describe(root)[0].Pool; stats.Outstanding != 1 {
+		t.Fatalf("Expected the consumer to hold its buffer, got %+v", stats)
+	}
+	if err := scope.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats := Describe(root)[0].Pool; stats.Outstanding != 0 || consumer.buffer.resets != 1 {
+		t.Errorf("Expected Close to release the buffer, got %+v", stats)
+	}
+}
+
+// TestInjectPooledChecksProfileFirst tests that services excluded by their profile are not constructed
+func TestInjectPooledChecksProfileFirst(t *testing.T) {
+	container := NewContainer(WithProfiles("prod"))
+	built := 0
+	InjectPooled(func() *pooledBuffer {
+		built++
+		return &pooledBuffer{}
+	}, container, Profile("dev"))
+
+	if built != 0 || container.Count() != 0 {
+		t.Errorf("Expected the excluded service not to be built, got %d builds", built)
+	}
+}
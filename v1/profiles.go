@@ -0,0 +1,61 @@
+package sioc
+
+import (
+	"os"
+	"strings"
+)
+
+// ProfilesEnv is the environment variable holding the comma-separated active profiles
+// of containers created without WithProfiles.
+const ProfilesEnv = "SIOC_PROFILES"
+
+// ActiveProfiles returns the profiles active in the container.
+func ActiveProfiles(serviceContainer ServiceContainer) []string {
+	return append([]string(nil), activeProfiles(serviceContainer)...)
+}
+
+// activeProfiles returns the container's profiles, or those from the environment for
+// containers other than the built-in registry.
+func activeProfiles(serviceContainer ServiceContainer) []string {
+	if registry, ok := registryOf(serviceContainer); ok {
+		return registry.profiles
+	}
+	return profilesFromEnv()
+}
+
+func profilesFromEnv() []string {
+	return normalizeProfiles(strings.Split(os.Getenv(ProfilesEnv), ","))
+}
+
+// normalizeProfiles trims the profile names and drops empty ones.
+func normalizeProfiles(profiles []string) []string {
+	var normalized []string
+	for _, profile := range profiles {
+		if profile = strings.TrimSpace(profile); profile != "" && profile != "!" {
+			normalized = append(normalized, profile)
+		}
+	}
+	return normalized
+}
+
+// profilesMatch reports whether the registration applies to the active profiles.
+// Registrations without profiles always apply.
+func (settings *injectSettings) profilesMatch(active []string) bool {
+	if len(settings.profiles) == 0 {
+		return true
+	}
+	activeSet := make(map[string]bool, len(active))
+	for _, profile := range active {
+		activeSet[profile] = true
+	}
+	for _, profile := range settings.profiles {
+		if negated, isNegated := strings.CutPrefix(profile, "!"); isNegated {
+			if !activeSet[negated] {
+				return true
+			}
+		} else if activeSet[profile] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package sioc
+
+import (
+	"testing"
+)
+
+type fakeMailer struct{}
+
+func (fakeMailer) Send() string { return "fake" }
+
+type smtpMailer struct{}
+
+func (smtpMailer) Send() string { return "smtp" }
+
+type Mailer interface {
+	Send() string
+}
+
+// TestProfileSelectsRegistrations tests that only registrations for active profiles apply
+func TestProfileSelectsRegistrations(t *testing.T) {
+	devContainer := NewContainer(WithProfiles("dev", "local"))
+	Inject(&fakeMailer{}, devContainer, Profile("dev"))
+	Inject(&smtpMailer{}, devContainer, Profile("prod"))
+
+	if mailer := Get[Mailer](devContainer); mailer.Send() != "fake" {
+		t.Errorf("Expected fake mailer in dev, got %v", mailer.Send())
+	}
+	if devContainer.Count() != 1 {
+		t.Errorf("Expected only the dev registration, got %d services", devContainer.Count())
+	}
+
+	prodContainer := NewContainer(WithProfiles("prod"))
+	Inject(&fakeMailer{}, prodContainer, Profile("dev", "test"))
+	Inject(&smtpMailer{}, prodContainer, Profile("prod"))
+
+	if mailer := Get[Mailer](prodContainer); mailer.Send() != "smtp" {
+		t.Errorf("Expected smtp mailer in prod, got %v", mailer.Send())
+	}
+}
+
+type fakeMailerProvider struct{}
+
+func (fakeMailerProvider) ProvideService() any { return &fakeMailer{} }
+
+// TestProfileSkipsProviders tests that a provider excluded by its profile provides nothing
+func TestProfileSkipsProviders(t *testing.T) {
+	container := NewContainer(WithProfiles("prod"))
+	Inject(&fakeMailerProvider{}, container, Profile("dev"))
+
+	if container.Count() != 0 {
+		t.Errorf("Expected the skipped provider to register nothing, got %d services", container.Count())
+	}
+}
+
+// TestProfileNegation tests profiles prefixed with "!"
+func TestProfileNegation(t *testing.T) {
+	container := NewContainer(WithProfiles("dev"))
+	Inject(&fakeMailer{}, container, Profile("!prod"))
+	Inject(&smtpMailer{}, container, Profile("!dev"))
+
+	if container.Count() != 1 {
+		t.Fatalf("Expected only the !prod registration, got %d services", container.Count())
+	}
+	if mailer := Get[Mailer](container); mailer.Send() != "fake" {
+		t.Errorf("Expected fake mailer, got %v", mailer.Send())
+	}
+}
+
+// TestProfilesFromEnvironment tests that SIOC_PROFILES selects the active profiles
+func TestProfilesFromEnvironment(t *testing.T) {
+	t.Setenv(ProfilesEnv, " prod , , eu ")
+	container := NewContainer()
+
+	profiles := ActiveProfiles(container)
+	if len(profiles) != 2 || profiles[0] != "prod" || profiles[1] != "eu" {
+		t.Errorf("Expected [prod eu], got %v", profiles)
+	}
+
+	Inject(&smtpMailer{}, container, Profile("prod"))
+	if container.Count() != 1 {
+		t.Error("Expected the prod registration to apply")
+	}
+
+	if scopeProfiles := ActiveProfiles(NewScope(container)); len(scopeProfiles) != 2 {
+		t.Errorf("Expected scopes to inherit profiles, got %v", scopeProfiles)
+	}
+
+	if overridden := ActiveProfiles(NewContainer(WithProfiles("dev"))); len(overridden) != 1 || overridden[0] != "dev" {
+		t.Errorf("Expected WithProfiles to override the environment, got %v", overridden)
+	}
+}
+
+// TestInjectIf tests conditional registration
+func TestInjectIf(t *testing.T) {
+	container := NewContainer()
+	InjectIf(false, &fakeMailer{}, container)
+	InjectIf(true, &smtpMailer{}, container)
+
+	if container.Count() != 1 {
+		t.Fatalf("Expected 1 service, got %d", container.Count())
+	}
+	if mailer := Get[Mailer](container); mailer.Send() != "smtp" {
+		t.Errorf("Expected smtp mailer, got %v", mailer.Send())
+	}
+}
@@ -0,0 +1,182 @@
+package sioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ProvidedServices can be returned by ServiceProvider.ProvideService to register several services.
+type ProvidedServices []any
+
+// registerProvided registers the services returned by the provider in the container, with the
+// container's lifetime. Providers among them are handled in turn. A panic in ProvideService
+// is returned as a PanicError.
+func registerProvided(serviceContainer ServiceContainer, provider ServiceProvider) error {
+	provided, err := provideServices(serviceContainer, provider)
+	if err != nil {
+		return err
+	}
+	services, ok := provided.(ProvidedServices)
+	if !ok {
+		services = ProvidedServices{provided}
+	}
+	for _, serviceInstance := range services {
+		if serviceInstance == nil || serviceInstance == any(provider) {
+			continue
+		}
+		Inject(serviceInstance, serviceContainer)
+	}
+	return nil
+}
+
+// provideServices calls ProvideService, converting a panic into a PanicError.
+func provideServices(serviceContainer ServiceContainer, provider ServiceProvider) (provided any, err error) {
+	defer recoverPanic(repanics(serviceContainer), reflect.TypeOf(provider), providerStage, &err)
+	return provider.ProvideService(), nil
+}
+
+// providePending initializes providers whose provision waits on their Init method, as soon as
+// their dependencies can be resolved, and registers what they provide. Provided services can
+// satisfy other providers, so it repeats until no provider makes progress. It reports whether
+// any service was provided, in this container or in one of its parents, and returns the errors
+// of the providers whose Init method failed, wrapped with the provider type.
+//
+// Each provider is claimed by one call at a time, so user Init code may itself resolve
+// services that other pending providers supply. A call that cannot provide anything itself
+// waits for the providers other lookups are initializing, and reports them as provided once
+// they succeed, so callers look again; a provider whose Init looks up a service only it
+// provides therefore blocks. The entry's retry policy applies only when retry is set, as it is
+// by Init; lookups call a provider's Init once. A provider whose Init gave up is not called
+// again, and its recorded error is returned.
+func providePending(serviceContainer ServiceContainer, retry bool) (bool, error) {
+	registry, ok := registryOf(serviceContainer)
+	if !ok {
+		return false, nil
+	}
+	provided := false
+	failures := map[*serviceEntry]error{}
+	for progress := true; progress; {
+		progress = false
+		dependencyMap := buildDependencyMap(serviceContainer)
+		for _, entry := range registry.entries() {
+			if !entry.claimProvision() {
+				continue
+			}
+			entryProvided, err := initializeEntry(serviceContainer, entry, dependencyMap, retry)
+			entry.releaseProvision()
+			if err != nil && !errors.Is(err, ErrDependencyNotFound) {
+				failures[entry] = err
+			}
+			if entryProvided {
+				progress, provided = true, true
+				break
+			}
+		}
+		if !progress && !provided {
+			if entry, done := inFlightProvision(registry); done != nil {
+				<-done
+				progress, provided = true, entry.isInitialized()
+			}
+		}
+	}
+
+	var provisionErrors []error
+	for _, entry := range registry.entries() {
+		err, found := failures[entry]
+		if !found {
+			err = entry.pendingProviderFailure()
+		}
+		if err != nil {
+			serviceType := reflect.TypeOf(entry.instance.(ServiceWrapper[any]).GetService())
+			provisionErrors = append(provisionErrors, fmt.Errorf("sioc: provider %v: %w", serviceType, err))
+		}
+	}
+
+	if parent := registry.fallback(); parent != nil {
+		parentProvided, err := providePending(parent, retry)
+		provided = provided || parentProvided
+		provisionErrors = append(provisionErrors, err)
+	}
+	return provided, errors.Join(provisionErrors...)
+}
+
+// inFlightProvision returns an entry whose provider another lookup is initializing, and the
+// channel closed when it is done.
+func inFlightProvision(registry *serviceRegistry) (*serviceEntry, <-chan struct{}) {
+	for _, entry := range registry.entries() {
+		if done := entry.provisionDone(); done != nil {
+			return entry, done
+		}
+	}
+	return nil, nil
+}
+
+// initializeEntry calls the Init method of the entry's service with its resolved dependencies,
+// then registers what it provides if it is a pending provider. It reports whether services
+// were provided. Resolution errors are returned before Init is called, and an error returned
+// by Init once the entry's retry policy is exhausted, or after a single attempt without retry.
+func initializeEntry(serviceContainer ServiceContainer, entry *serviceEntry, dependencyMap map[reflect.Type]ServiceWrapper[any], retry bool) (bool, error) {
+	wrapper := entry.instance.(ServiceWrapper[any])
+	serviceInstance := wrapper.GetService()
+	serviceType := reflect.TypeOf(serviceInstance)
+
+	initializationMethod := reflect.ValueOf(serviceInstance).MethodByName("Init")
+	if initializationMethod.IsValid() {
+		methodParams, leases, err := resolveArguments(initializationMethod.Type(), dependencyMap)
+		if err != nil {
+			return false, withPath(err, serviceType)
+		}
+		policy := retryPolicy{attempts: 1}
+		if retry {
+			policy = entry.retryPolicy()
+		}
+		if err := callInitWithRetry(serviceContainer, entry, policy, serviceType, initializationMethod, methodParams); err != nil {
+			releaseLeases(leases)
+			return false, err
+		}
+		// The service keeps its pooled dependencies; a scope returns them when it is closed.
+		if scope, ok := serviceContainer.(*scopeRegistry); ok {
+			for _, lease := range leases {
+				scope.lease(lease.pool, lease.service)
+			}
+		}
+	}
+
+	if provider := entry.takePendingProvider(); provider != nil {
+		if err := registerProvided(serviceContainer, provider); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// callInitWithRetry calls the Init method until it succeeds or the retry policy is exhausted,
+// reporting every attempt. It marks the entry initialized on success, and records the last
+// error on the entry when it gives up.
+func callInitWithRetry(serviceContainer ServiceContainer, entry *serviceEntry, policy retryPolicy, serviceType reflect.Type, initializationMethod reflect.Value, methodParams []reflect.Value) error {
+	events := eventsOf(serviceContainer)
+	for attempt := 1; ; attempt++ {
+		events.initStarted(serviceType)
+		serviceStartedAt := time.Now()
+		err := callInit(serviceContainer, serviceType, initializationMethod, methodParams)
+		initDuration := time.Since(serviceStartedAt)
+		if err == nil {
+			entry.markInitialized(initDuration, argumentTypes(methodParams))
+			events.initDone(serviceType, initDuration)
+			return nil
+		}
+
+		attemptErr := &InitAttemptError{ServiceType: serviceType, Attempt: attempt, Attempts: policy.attempts, Err: err}
+		events.failed("service init attempt failed", serviceType, attemptErr)
+		// A panic is a bug in the service, which waiting will not fix.
+		var panicErr *PanicError
+		if attempt >= policy.attempts || errors.As(err, &panicErr) {
+			entry.recordInitFailure(attemptErr)
+			return attemptErr
+		}
+		time.Sleep(policy.delay(attempt))
+	}
+}
@@ -0,0 +1,246 @@
+package sioc
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type providedClient struct {
+	address string
+}
+
+type providedCache struct{}
+
+type staticProvider struct{}
+
+func (sp *staticProvider) ProvideService() any {
+	return ProvidedServices{&providedClient{address: "static"}, &providedCache{}}
+}
+
+type configuredProvider struct {
+	config *TestStruct
+	inits  int
+}
+
+func (cp *configuredProvider) Init(config *TestStruct) {
+	cp.config = config
+	cp.inits++
+}
+
+func (cp *configuredProvider) ProvideService() any {
+	return &providedClient{address: cp.config.Value}
+}
+
+type providedConsumer struct {
+	client *providedClient
+}
+
+func (pc *providedConsumer) Init(client *providedClient) {
+	pc.client = client
+}
+
+type chainedProvider struct {
+	client *providedClient
+}
+
+func (cp *chainedProvider) Init(client *providedClient) {
+	cp.client = client
+}
+
+func (cp *chainedProvider) ProvideService() any {
+	return &closableService{}
+}
+
+type failingProvider struct{}
+
+func (fp *failingProvider) Init() error {
+	return errBrokerDown
+}
+
+func (fp *failingProvider) ProvideService() any {
+	return &providedClient{}
+}
+
+type slowProvider struct {
+	config *TestStruct
+}
+
+func (sp *slowProvider) Init(config *TestStruct) {
+	time.Sleep(50 * time.Millisecond)
+	sp.config = config
+}
+
+func (sp *slowProvider) ProvideService() any {
+	return &providedClient{address: sp.config.Value}
+}
+
+type nestedProvider struct {
+	container ServiceContainer
+	client    *providedClient
+}
+
+func (np *nestedProvider) Init() {
+	np.client = Get[*providedClient](np.container)
+}
+
+func (np *nestedProvider) ProvideService() any {
+	return &providedCache{}
+}
+
+// TestInjectRegistersProvidedServices tests that providers without Init provide right away
+func TestInjectRegistersProvidedServices(t *testing.T) {
+	container := NewContainer()
+	provider := &staticProvider{}
+	Inject(provider, container)
+
+	if container.Count() != 3 {
+		t.Errorf("Expected the provider and 2 provided services, got %d", container.Count())
+	}
+	if client := Get[*providedClient](container); client.address != "static" {
+		t.Errorf("Expected the provided client, got %+v", client)
+	}
+	Get[*providedCache](container)
+	if Get[*staticProvider](container) != provider {
+		t.Error("Expected the provider to stay registered")
+	}
+}
+
+// TestProviderWaitsForInit tests that providers with Init provide on the first lookup that needs them
+func TestProviderWaitsForInit(t *testing.T) {
+	container := NewContainer()
+	provider := &configuredProvider{}
+	Inject(provider, container)
+	Inject(&TestStruct{Value: "configured"}, container)
+
+	if container.Count() != 2 {
+		t.Fatalf("Expected provision to wait for Init, got %d services", container.Count())
+	}
+	if client := Get[*providedClient](container); client.address != "configured" {
+		t.Errorf("Expected the client built from the config, got %+v", client)
+	}
+
+	Init(container)
+	if provider.inits != 1 {
+		t.Errorf("Expected the provider to be initialized once, got %d", provider.inits)
+	}
+}
+
+// TestInitInitializesProvidersFirst tests that Init resolves dependencies supplied by pending providers
+func TestInitInitializesProvidersFirst(t *testing.T) {
+	container := NewContainer()
+	consumer := &providedConsumer{}
+	chained := &chainedProvider{}
+	Inject(consumer, container)
+	Inject(chained, container)
+	Inject(&configuredProvider{}, container)
+	Inject(&TestStruct{Value: "configured"}, container)
+
+	Init(container)
+
+	if consumer.client == nil || consumer.client.address != "configured" {
+		t.Errorf("Expected the consumer to receive the provided client, got %+v", consumer.client)
+	}
+	if chained.client != consumer.client {
+		t.Error("Expected both services to share the provided client")
+	}
+	Get[*closableService](container)
+}
+
+// TestScopedProviderServicesAreDisposed tests that provided services take the lifetime of the scope
+func TestScopedProviderServicesAreDisposed(t *testing.T) {
+	root := NewContainer()
+	Inject(&TestStruct{Value: "root"}, root)
+	Inject(&configuredProvider{}, root)
+
+	scope := NewScope(root)
+	Inject(&chainedProvider{}, scope)
+	Init(scope)
+
+	closable := Get[*closableService](scope)
+	described := false
+	for _, info := range Describe(scope) {
+		if info.Type == "*sioc.closableService" {
+			described = true
+			if info.Lifetime != Scoped {
+				t.Errorf("Expected the provided service to be scoped, got %v", info.Lifetime)
+			}
+		}
+	}
+	if !described {
+		t.Error("Expected the provided service to be registered in the scope")
+	}
+	if err := scope.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if closable.closed != 1 {
+		t.Errorf("Expected the provided service to be closed with the scope, got %d", closable.closed)
+	}
+}
+
+// TestProviderInitErrorIsReturned tests that lookups report why a pending provider failed
+func TestProviderInitErrorIsReturned(t *testing.T) {
+	container := NewContainer()
+	Inject(&failingProvider{}, container)
+
+	err := Invoke(container, func(*providedClient) {})
+	if !errors.Is(err, errBrokerDown) || errors.Is(err, ErrDependencyNotFound) {
+		t.Fatalf("Expected the provider's Init error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "provider *sioc.failingProvider") {
+		t.Errorf("Expected the error to name the provider, got %v", err)
+	}
+}
+
+// TestNestedPendingProviders tests that a provider's Init can resolve services of another pending provider
+func TestNestedPendingProviders(t *testing.T) {
+	container := NewContainer()
+	nested := &nestedProvider{container: container}
+	Inject(nested, container)
+	Inject(&configuredProvider{}, container)
+	Inject(&TestStruct{Value: "configured"}, container)
+
+	resolved := make(chan *providedCache)
+	go func() {
+		resolved <- Get[*providedCache](container)
+	}()
+	select {
+	case <-resolved:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected nested provision not to deadlock")
+	}
+	if nested.client == nil || nested.client.address != "configured" {
+		t.Errorf("Expected the nested lookup to resolve the provided client, got %+v", nested.client)
+	}
+}
+
+// TestConcurrentLookupsWaitForProvision tests that lookups wait for a provider another lookup is initializing
+func TestConcurrentLookupsWaitForProvision(t *testing.T) {
+	container := NewContainer()
+	Inject(&slowProvider{}, container)
+	Inject(&TestStruct{Value: "slow"}, container)
+
+	var waitGroup sync.WaitGroup
+	clients := make(chan *providedClient, 20)
+	for lookup := 0; lookup < 20; lookup++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			clients <- Get[*providedClient](container)
+		}()
+	}
+	waitGroup.Wait()
+	close(clients)
+
+	var first *providedClient
+	for client := range clients {
+		if first == nil {
+			first = client
+		}
+		if client != first || client.address != "slow" {
+			t.Errorf("Expected every lookup to get the provided client, got %+v", client)
+		}
+	}
+}
@@ -1,32 +1,86 @@
 package sioc
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"reflect"
 	"runtime"
 	"strings"
+	"time"
+
+	"github.com/sergiodii/sioc/extension/typekey"
 )
 
+// ErrDependencyNotFound is returned when a parameter cannot be resolved from the container.
+var ErrDependencyNotFound = errors.New("dependency not found")
+
 // Get retrieves a service instance of type T from the container.
 // It checks for both direct type and interface implementations.
+// Resolutions are cached per container and invalidated on registration.
 func Get[T any](serviceContainer ServiceContainer) T {
 	targetType := reflect.TypeOf((*T)(nil)).Elem()
 
-	if serviceInstance, found := serviceContainer.Resolve(targetType.String()); found {
+	registry, cacheable := registryOf(serviceContainer)
+	var generation uint64
+	if cacheable {
+		if accessor, found := registry.cachedResolution(targetType); found {
+			if len(registry.events.hooks) > 0 {
+				registry.events.resolved(targetType, true)
+			}
+			return accessor.(func() T)()
+		}
+		generation = registry.currentGeneration()
+	}
+
+	events := eventsOf(serviceContainer)
+	accessor, found := findService[T](serviceContainer, targetType)
+	var provisionErr error
+	// Pending providers may supply the service; look again after each one that provides.
+	for !found {
+		var provided bool
+		provided, provisionErr = providePending(serviceContainer, false)
+		if !provided {
+			break
+		}
+		if cacheable {
+			generation = registry.currentGeneration()
+		}
+		accessor, found = findService[T](serviceContainer, targetType)
+	}
+	if !found && provisionErr != nil {
+		events.failed("service not found", targetType, provisionErr)
+		log.Fatalf("Service of type %s not found in container: %v%s", targetType, provisionErr, panicStack(provisionErr))
+	}
+	if !found {
+		events.failed("service not found", targetType, fmt.Errorf("%w: %v", ErrDependencyNotFound, targetType))
+		log.Fatalf("Service of type %s not found in container", targetType)
+		var emptyService T
+		return emptyService
+	}
+
+	if cacheable {
+		registry.cacheResolution(targetType, generation, accessor)
+	}
+	events.resolved(targetType, false)
+	return accessor()
+}
+
+// findService searches the container for a service assignable to T and returns
+// an accessor that reads it from the matched wrapper.
+func findService[T any](serviceContainer ServiceContainer, targetType reflect.Type) (func() T, bool) {
+	if serviceInstance, found := serviceContainer.Resolve(typekey.Of(targetType)); found {
 		if wrapper, ok := serviceInstance.(ServiceWrapper[T]); ok {
-			fmt.Println("Achei aqui 1")
-			return wrapper.GetService()
+			return wrapper.GetService, true
 		}
 		if wrapperPtr, ok := serviceInstance.(ServiceWrapper[*T]); ok {
-			fmt.Println("Achei aqui 2")
-			return *wrapperPtr.GetService()
+			return func() T { return *wrapperPtr.GetService() }, true
 		}
 		// Also try ServiceWrapper[any] for backward compatibility
 		if wrapperAny, ok := serviceInstance.(ServiceWrapper[any]); ok {
-			service := wrapperAny.GetService()
-			if typedService, ok := service.(T); ok {
-				return typedService
+			if _, ok := wrapperAny.GetService().(T); ok {
+				return serviceAccessor[T](serviceContainer, wrapperAny), true
 			}
 		}
 	}
@@ -36,14 +90,15 @@ func Get[T any](serviceContainer ServiceContainer) T {
 		// Try ServiceWrapper[any] first (most common case)
 		if wrapperAny, ok := registeredService.(ServiceWrapper[any]); ok {
 			serviceInstance := wrapperAny.GetService()
+			accessor := serviceAccessor[T](serviceContainer, wrapperAny)
 			// Check direct type match
-			if typedService, ok := serviceInstance.(T); ok {
-				return typedService
+			if _, ok := serviceInstance.(T); ok {
+				return accessor, true
 			}
 			// Check interface implementation
 			if targetType.Kind() == reflect.Interface && reflect.TypeOf(serviceInstance).Implements(targetType) {
-				if typedService, ok := serviceInstance.(T); ok {
-					return typedService
+				if _, ok := serviceInstance.(T); ok {
+					return accessor, true
 				}
 			}
 
@@ -52,12 +107,12 @@ func Get[T any](serviceContainer ServiceContainer) T {
 				typeT := targetType.Elem()
 				// Verifica se o tipo do serviço é um ponteiro para o tipo desejado
 				if reflect.TypeOf(serviceInstance) == reflect.PtrTo(typeT) {
-					return serviceInstance.(T)
+					return accessor, true
 				}
 			} else {
 				// Para tipos não ponteiros, verifica o tipo base
 				if reflect.TypeOf(serviceInstance) == targetType {
-					return serviceInstance.(T)
+					return accessor, true
 				}
 
 				// if item.(v1_interfaces.Injector[T]).MatchWithName("*" + typeT.String()) {
@@ -70,27 +125,122 @@ func Get[T any](serviceContainer ServiceContainer) T {
 		if wrapper, ok := registeredService.(ServiceWrapper[T]); ok {
 			serviceInstance := wrapper.GetService()
 			if targetType.Kind() == reflect.Interface && reflect.TypeOf(serviceInstance).Implements(targetType) {
-				return serviceInstance
+				return wrapper.GetService, true
 			}
 		}
 		if wrapperPtr, ok := registeredService.(ServiceWrapper[*T]); ok {
 			serviceInstance := wrapperPtr.GetService()
 			if targetType.Kind() == reflect.Interface && reflect.TypeOf(serviceInstance).Implements(targetType) {
-				return *serviceInstance
+				return func() T { return *wrapperPtr.GetService() }, true
 			}
 		}
 	}
 
-	log.Fatalf("Service of type %s not found in container", targetType)
-	var emptyService T
-	return emptyService
+	return nil, false
+}
+
+// serviceAccessor returns the function Get uses to read the service from its wrapper.
+// Pooled services are acquired on every call, and leased to the scope they are resolved
+// from. Scopes also keep a reference to the instances they resolve, so a swapped instance
+// is not disposed while they are open.
+func serviceAccessor[T any](serviceContainer ServiceContainer, wrapper ServiceWrapper[any]) func() T {
+	scope, scoped := serviceContainer.(*scopeRegistry)
+	switch wrapper := wrapper.(type) {
+	case *pooledService:
+		return func() T {
+			service, err := wrapper.acquire()
+			if err != nil {
+				eventsOf(serviceContainer).failed("service construction failed", wrapper.serviceType, err)
+				panic(err)
+			}
+			if scoped {
+				scope.lease(wrapper, service)
+			}
+			return service.(T)
+		}
+	case *serviceWrapper[any]:
+		if scoped {
+			return func() T { return scope.reference(wrapper).(T) }
+		}
+	}
+	return func() T { return wrapper.GetService().(T) }
 }
 
 // Inject registers a service instance in the container, wrapping it in a ServiceWrapper.
-func Inject(serviceInstance any, serviceContainer ServiceContainer) {
+// Options such as Profile can make the registration conditional.
+func Inject(serviceInstance any, serviceContainer ServiceContainer, options ...InjectOption) {
+	serviceType := reflect.TypeOf(serviceInstance)
+	registerService(serviceContainer, serviceType, serviceInstance, options)
+}
+
+// InjectAs registers a service instance under the type T instead of its concrete type,
+// so values such as a context.Context resolve directly through their interface.
+func InjectAs[T any](serviceInstance T, serviceContainer ServiceContainer, options ...InjectOption) {
+	serviceType := reflect.TypeOf((*T)(nil)).Elem()
+	registerService(serviceContainer, serviceType, serviceInstance, options)
+}
+
+// InjectIf registers the service instance only when condition is true.
+func InjectIf(condition bool, serviceInstance any, serviceContainer ServiceContainer, options ...InjectOption) {
+	if condition {
+		Inject(serviceInstance, serviceContainer, options...)
+	}
+}
+
+// registerService wraps the instance and registers it under the service type's name,
+// unless the options exclude it from this container.
+func registerService(serviceContainer ServiceContainer, serviceType reflect.Type, serviceInstance any, options []InjectOption) {
 	wrapper := NewServiceWrapper[any]()
 	wrapper.SetService(serviceInstance)
-	serviceContainer.Register(reflect.TypeOf(serviceInstance).String(), wrapper)
+	entry, found, registered := registerWrapper(serviceContainer, serviceType, wrapper, options)
+	// A provider excluded by its profile provides nothing either.
+	if !registered {
+		return
+	}
+
+	// Providers with an Init method provide once it has run, during Init or on a lookup
+	// that needs them; the others provide right away.
+	if provider, ok := serviceInstance.(ServiceProvider); ok {
+		if found && !entry.isInitialized() && reflect.ValueOf(serviceInstance).MethodByName("Init").IsValid() {
+			entry.setPendingProvider(provider)
+		} else if err := registerProvided(serviceContainer, provider); err != nil {
+			eventsOf(serviceContainer).failed("service provider failed", serviceType, err)
+			log.Fatalf("Provider %v: %v%s", serviceType, err, panicStack(err))
+		}
+	}
+}
+
+// registerWrapper registers the wrapper under the canonical key of the service type, unless
+// the inject options exclude it. It returns the entry created for it, if the container is a
+// built-in one, and reports whether the wrapper was registered at all.
+func registerWrapper(serviceContainer ServiceContainer, serviceType reflect.Type, wrapper ServiceWrapper[any], options []InjectOption) (entry *serviceEntry, found bool, registered bool) {
+	settings := newInjectSettings(options)
+	events := eventsOf(serviceContainer)
+	if skipsRegistration(serviceContainer, serviceType, settings) {
+		return nil, false, false
+	}
+
+	serviceKey := typekey.Of(serviceType)
+	serviceContainer.Register(serviceKey, wrapper)
+	entry, found = entryOf(serviceContainer, serviceKey)
+	if found {
+		entry.setRetryPolicy(settings.retry)
+		if settings.initialized {
+			entry.markInitialized(0, nil)
+		}
+	}
+	events.registered(serviceType)
+	return entry, found, true
+}
+
+// skipsRegistration reports, and logs, whether the profiles of the inject options exclude the
+// service from the container.
+func skipsRegistration(serviceContainer ServiceContainer, serviceType reflect.Type, settings *injectSettings) bool {
+	if settings.profilesMatch(activeProfiles(serviceContainer)) {
+		return false
+	}
+	eventsOf(serviceContainer).logger.Debug("service skipped", slog.String("service", serviceType.String()), slog.Any("profiles", settings.profiles))
+	return true
 }
 
 // GetFunctionName returns the name of a function from its value.
@@ -102,37 +252,209 @@ func GetFunctionName(functionValue interface{}) string {
 }
 
 // Init calls the Init method on all registered services that have it, resolving dependencies.
+// Services that were already initialized by a previous call are skipped, and every service is
+// initialized after the services its Init method receives. Services registered by providers
+// during Init are initialized as well. An Init method that returns an error or
+// panics stops Init with a fatal log naming the service; see InitE, WithRetry and PanicError.
 func Init(serviceContainer ServiceContainer) {
+	if err := InitE(serviceContainer); err != nil {
+		log.Fatalf("%v%s", err, panicStack(err))
+	}
+}
+
+// InitE is Init returning the error that stops it, naming the service, instead of ending the
+// process. Services initialized before the failure stay initialized, so a later call resumes.
+func InitE(serviceContainer ServiceContainer) error {
+	events := eventsOf(serviceContainer)
+	dependencyMap := buildDependencyMap(serviceContainer)
+	initStartedAt := time.Now()
+	initializedCount := 0
+	events.logger.Info("init started", slog.Int("services", len(dependencyMap)))
+
+	// Failures recorded by earlier lookups, which call Init once, get the full retry policy here.
+	for _, entry := range entriesOf(serviceContainer) {
+		entry.clearInitFailure()
+	}
+
+	for provided := true; provided; {
+		provided = false
+		for _, entry := range initOrder(entriesOf(serviceContainer), dependencyMap) {
+			wrapper, ok := entry.instance.(ServiceWrapper[any])
+			if !ok || entry.isInitialized() || entry.lifetime == Pooled {
+				continue
+			}
+			serviceInstance := wrapper.GetService()
+			if !reflect.ValueOf(serviceInstance).MethodByName("Init").IsValid() {
+				continue
+			}
+
+			// A missing dependency may come from a provider that is still pending. A provider
+			// that already gave up while providing for another service is not called again.
+			entryProvided, err := false, entry.initFailure()
+			if err == nil {
+				entryProvided, err = initializeEntry(serviceContainer, entry, dependencyMap, true)
+			}
+			if errors.Is(err, ErrDependencyNotFound) {
+				pendingProvided, provisionErr := providePending(serviceContainer, true)
+				if pendingProvided {
+					provided = true
+					dependencyMap = buildDependencyMap(serviceContainer)
+					if entry.isInitialized() {
+						initializedCount++
+						continue
+					}
+					entryProvided, err = initializeEntry(serviceContainer, entry, dependencyMap, true)
+				}
+				// The dependency is missing because its provider failed
+				if errors.Is(err, ErrDependencyNotFound) && provisionErr != nil {
+					err = provisionErr
+				}
+			}
+			if err != nil {
+				serviceType := reflect.TypeOf(serviceInstance)
+				events.failed("service init failed", serviceType, err)
+				return fmt.Errorf("sioc: init of %v: %w", serviceType, err)
+			}
+			if entryProvided {
+				provided = true
+				dependencyMap = buildDependencyMap(serviceContainer)
+			}
+			initializedCount++
+		}
+	}
+
+	events.logger.Info("init finished", slog.Int("initialized", initializedCount), slog.Duration("duration", time.Since(initStartedAt)))
+	return nil
+}
+
+// initOrder sorts the entries so each comes after the entries its Init method receives, so
+// services are initialized before they are passed to their dependents. A dependency cycle is
+// broken at the entry it was entered from.
+func initOrder(entries []*serviceEntry, dependencyMap map[reflect.Type]ServiceWrapper[any]) []*serviceEntry {
+	entryByType := make(map[reflect.Type]*serviceEntry, len(entries))
+	for _, entry := range entries {
+		if wrapper, ok := entry.instance.(ServiceWrapper[any]); ok {
+			entryByType[reflect.TypeOf(wrapper.GetService())] = entry
+		}
+	}
+
+	ordered := make([]*serviceEntry, 0, len(entries))
+	visited := make(map[*serviceEntry]bool, len(entries))
+	var visit func(entry *serviceEntry)
+	visit = func(entry *serviceEntry) {
+		if visited[entry] {
+			return
+		}
+		visited[entry] = true
+		if wrapper, ok := entry.instance.(ServiceWrapper[any]); ok {
+			if initializationMethod := reflect.ValueOf(wrapper.GetService()).MethodByName("Init"); initializationMethod.IsValid() {
+				methodType := initializationMethod.Type()
+				for paramIndex := 0; paramIndex < methodType.NumIn(); paramIndex++ {
+					dependency, exists := findDependency(methodType.In(paramIndex), dependencyMap)
+					if !exists {
+						continue
+					}
+					if dependencyEntry, found := entryByType[reflect.TypeOf(dependency.GetService())]; found {
+						visit(dependencyEntry)
+					}
+				}
+			}
+		}
+		ordered = append(ordered, entry)
+	}
+	for _, entry := range entries {
+		visit(entry)
+	}
+	return ordered
+}
+
+// buildDependencyMap indexes the registered services by their concrete type.
+func buildDependencyMap(serviceContainer ServiceContainer) map[reflect.Type]ServiceWrapper[any] {
 	dependencyMap := make(map[reflect.Type]ServiceWrapper[any])
 	for _, registeredService := range serviceContainer.ListAll() {
 		if wrapper, ok := registeredService.(ServiceWrapper[any]); ok {
 			dependencyMap[reflect.TypeOf(wrapper.GetService())] = wrapper
 		}
 	}
+	return dependencyMap
+}
 
-	for _, registeredService := range serviceContainer.ListAll() {
-		wrapper, ok := registeredService.(ServiceWrapper[any])
-		if !ok {
+var instanceCreationModeType = reflect.TypeOf(CreateNewInstance)
+
+// resolveArguments resolves every parameter of the function type from the dependency map.
+// Parameters are matched by exact type first, then by interface implementation. An
+// InstanceCreationMode parameter receives CreateNewInstance, and the parameter following
+// it receives a new instance of its dependency instead of the shared one. Pooled arguments
+// are returned as leases, which the caller releases once it no longer needs them; on error
+// they are released already.
+func resolveArguments(functionType reflect.Type, dependencyMap map[reflect.Type]ServiceWrapper[any]) ([]reflect.Value, []poolLease, error) {
+	arguments := make([]reflect.Value, functionType.NumIn())
+	var leases []poolLease
+	for paramIndex := 0; paramIndex < functionType.NumIn(); paramIndex++ {
+		parameterType := functionType.In(paramIndex)
+		if parameterType == instanceCreationModeType {
+			arguments[paramIndex] = reflect.ValueOf(CreateNewInstance)
 			continue
 		}
-		serviceInstance := wrapper.GetService()
-		initializationMethod := reflect.ValueOf(serviceInstance).MethodByName("Init")
-		if !initializationMethod.IsValid() {
-			continue
+		dependency, exists := findDependency(parameterType, dependencyMap)
+		if !exists {
+			releaseLeases(leases)
+			return nil, nil, fmt.Errorf("%w: %v", ErrDependencyNotFound, parameterType)
+		}
+		var service any
+		var err error
+		if paramIndex > 0 && functionType.In(paramIndex-1) == instanceCreationModeType {
+			service, err = newServiceOf(dependency)
+		} else {
+			service, err = serviceOf(dependency)
 		}
+		if err != nil {
+			releaseLeases(leases)
+			return nil, nil, err
+		}
+		if pooled, ok := dependency.(*pooledService); ok {
+			leases = append(leases, poolLease{pool: pooled, service: service})
+		}
+		arguments[paramIndex] = reflect.ValueOf(service)
+	}
+	return arguments, leases, nil
+}
 
-		methodType := initializationMethod.Type()
-		methodParams := make([]reflect.Value, methodType.NumIn())
-		for paramIndex := 0; paramIndex < methodType.NumIn(); paramIndex++ {
-			parameterType := methodType.In(paramIndex)
-			dependency, exists := dependencyMap[parameterType]
-			if !exists {
-				continue
-			}
-			methodParams[paramIndex] = reflect.ValueOf(dependency.GetService())
+// newServiceOf returns a new instance of the dependency, reporting copy failures as errors
+// instead of the panic of CreateNewService.
+func newServiceOf(dependency ServiceWrapper[any]) (any, error) {
+	switch wrapper := dependency.(type) {
+	case *serviceWrapper[any]:
+		return wrapper.cloneService()
+	case *pooledService:
+		return wrapper.acquire()
+	}
+	return dependency.CreateNewService(), nil
+}
+
+// argumentTypes returns the concrete types of the resolved arguments.
+func argumentTypes(arguments []reflect.Value) []reflect.Type {
+	types := make([]reflect.Type, len(arguments))
+	for index, argument := range arguments {
+		types[index] = argument.Type()
+	}
+	return types
+}
+
+// findDependency returns the wrapper whose service satisfies the parameter type.
+func findDependency(parameterType reflect.Type, dependencyMap map[reflect.Type]ServiceWrapper[any]) (ServiceWrapper[any], bool) {
+	if dependency, exists := dependencyMap[parameterType]; exists {
+		return dependency, true
+	}
+	if parameterType.Kind() != reflect.Interface {
+		return nil, false
+	}
+	for serviceType, dependency := range dependencyMap {
+		if serviceType.Implements(parameterType) {
+			return dependency, true
 		}
-		initializationMethod.Call(methodParams)
 	}
+	return nil, false
 }
 
 // func Init2(container Container) {
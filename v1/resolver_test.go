@@ -1,8 +1,13 @@
 package sioc
 
 import (
+	"errors"
+	htmltemplate "html/template"
 	"reflect"
 	"testing"
+	texttemplate "text/template"
+
+	"github.com/sergiodii/sioc/extension/clone"
 )
 
 // Test interfaces and structs for testing
@@ -284,6 +289,31 @@ func TestInitWithDependencies(t *testing.T) {
 	}
 }
 
+type TestStructInitOrder struct {
+	dependencyInitialized bool
+}
+
+func (tso *TestStructInitOrder) Init(dep *TestStruct) {
+	tso.dependencyInitialized = dep.initialized
+}
+
+// TestInitInitializesDependenciesFirst tests that dependencies are initialized before their dependents
+func TestInitInitializesDependenciesFirst(t *testing.T) {
+	// Registries are unordered, so a lucky order must not hide a failure
+	for attempt := 0; attempt < 20; attempt++ {
+		container := NewContainer()
+		service := &TestStructInitOrder{}
+
+		Inject(service, container)
+		Inject(&TestStruct{Value: "dependency"}, container)
+		Init(container)
+
+		if !service.dependencyInitialized {
+			t.Fatal("Expected the dependency to be initialized before it was passed to Init")
+		}
+	}
+}
+
 // TestInitializeServices tests new initialization method name
 func TestInitializeServices(t *testing.T) {
 	container := NewContainer()
@@ -325,6 +355,119 @@ func TestInstanceCreationMode(t *testing.T) {
 	}
 }
 
+type TestStructWithNewInstance struct {
+	Mode       InstanceCreationMode
+	Dependency *TestStruct
+}
+
+func (tsn *TestStructWithNewInstance) Init(mode InstanceCreationMode, dep *TestStruct) {
+	tsn.Mode = mode
+	tsn.Dependency = dep
+}
+
+// TestInitWithInstanceCreationMode tests that Init receives the marker and the dependency after it
+func TestInitWithInstanceCreationMode(t *testing.T) {
+	container := NewContainer()
+	Inject(&TestStruct{Value: "dependency"}, container)
+	Inject(&TestStructWithNewInstance{}, container)
+
+	Init(container)
+
+	service := Get[*TestStructWithNewInstance](container)
+	if service.Mode != CreateNewInstance {
+		t.Errorf("Expected mode %v, got %v", CreateNewInstance, service.Mode)
+	}
+	if service.Dependency == nil || service.Dependency.Value != "dependency" {
+		t.Errorf("Expected the dependency to be injected, got %+v", service.Dependency)
+	}
+}
+
+type clonedDependency struct {
+	Items  []string
+	clones int
+}
+
+func (cd *clonedDependency) Clone() any {
+	cd.clones++
+	return &clonedDependency{Items: []string{"cloned"}}
+}
+
+type channelDependency struct {
+	events chan string
+}
+
+// TestInitWithInstanceCreationModeCopiesDependency tests that the marked parameter shares nothing with the singleton
+func TestInitWithInstanceCreationModeCopiesDependency(t *testing.T) {
+	container := NewContainer()
+	singleton := &TestStruct{Value: "dependency"}
+	Inject(singleton, container)
+	Inject(&TestStructWithNewInstance{}, container)
+	Init(container)
+
+	service := Get[*TestStructWithNewInstance](container)
+	if service.Dependency == singleton {
+		t.Fatal("Expected a new instance of the dependency")
+	}
+	service.Dependency.Value = "changed"
+	if singleton.Value != "dependency" {
+		t.Errorf("Expected the singleton to be unchanged, got %v", singleton.Value)
+	}
+}
+
+// TestInstanceCreationModeUsesCloner tests that services implementing Cloner build their own new instances
+func TestInstanceCreationModeUsesCloner(t *testing.T) {
+	container := NewContainer()
+	singleton := &clonedDependency{Items: []string{"singleton"}}
+	Inject(singleton, container)
+
+	err := Invoke(container, func(_ InstanceCreationMode, dependency *clonedDependency) {
+		if dependency.Items[0] != "cloned" {
+			t.Errorf("Expected the instance built by Clone, got %v", dependency.Items)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if singleton.clones != 1 {
+		t.Errorf("Expected Clone to be called once, got %d", singleton.clones)
+	}
+}
+
+type factoryDependency struct {
+	events chan string
+}
+
+func (fd *factoryDependency) NewInstance() any {
+	return &factoryDependency{events: make(chan string)}
+}
+
+// TestInstanceCreationModeUsesInstanceFactory tests that the v0 IInstanceFactory is honored like Cloner
+func TestInstanceCreationModeUsesInstanceFactory(t *testing.T) {
+	container := NewContainer()
+	singleton := &factoryDependency{events: make(chan string)}
+	Inject(singleton, container)
+
+	err := Invoke(container, func(_ InstanceCreationMode, dependency *factoryDependency) {
+		if dependency == singleton || dependency.events == singleton.events {
+			t.Error("Expected the instance built by NewInstance")
+		}
+	})
+	if err != nil {
+		t.Fatalf("Expected NewInstance to replace the deep copy, got %v", err)
+	}
+}
+
+// TestInstanceCreationModeRejectsChannels tests that uncloneable services are reported
+func TestInstanceCreationModeRejectsChannels(t *testing.T) {
+	container := NewContainer()
+	Inject(&channelDependency{events: make(chan string)}, container)
+
+	err := Invoke(container, func(_ InstanceCreationMode, _ *channelDependency) {})
+	if !errors.Is(err, clone.ErrNotCloneable) {
+		t.Errorf("Expected ErrNotCloneable, got %v", err)
+	}
+}
+
 // TestContainerCount tests container counting functionality
 func TestContainerCount(t *testing.T) {
 	container := NewContainer()
@@ -380,3 +523,126 @@ func TestCreateNewService(t *testing.T) {
 		t.Errorf("Original should remain %v, got %v", testValue, original)
 	}
 }
+
+// TestGetCacheInvalidatedOnRegister tests that a new registration replaces a cached resolution
+func TestGetCacheInvalidatedOnRegister(t *testing.T) {
+	container := NewContainer()
+
+	Inject(&TestStruct{Value: "first"}, container)
+	if retrieved := Get[*TestStruct](container); retrieved.Value != "first" {
+		t.Fatalf("Expected 'first', got %v", retrieved.Value)
+	}
+
+	// Overwriting the registration must not return the cached service
+	Inject(&TestStruct{Value: "second"}, container)
+	if retrieved := Get[*TestStruct](container); retrieved.Value != "second" {
+		t.Errorf("Expected 'second' after re-registration, got %v", retrieved.Value)
+	}
+
+	if retrieved := Get[TestInterface](container); retrieved.GetValue() != "second" {
+		t.Errorf("Expected interface resolution 'second', got %v", retrieved.GetValue())
+	}
+}
+
+// TestGetCacheReflectsWrapperChanges tests that cached resolutions read the current wrapper value
+func TestGetCacheReflectsWrapperChanges(t *testing.T) {
+	container := NewContainer()
+	wrapper := NewServiceWrapper[any]()
+	wrapper.SetService(&TestStruct{Value: "before"})
+	container.Register("wrapper", wrapper)
+
+	if retrieved := Get[*TestStruct](container); retrieved.Value != "before" {
+		t.Fatalf("Expected 'before', got %v", retrieved.Value)
+	}
+
+	wrapper.SetService(&TestStruct{Value: "after"})
+	if retrieved := Get[*TestStruct](container); retrieved.Value != "after" {
+		t.Errorf("Expected 'after', got %v", retrieved.Value)
+	}
+}
+
+func newBenchmarkContainer() ServiceContainer {
+	container := NewContainer()
+	Inject(&TestStruct{Value: "bench"}, container)
+	Inject(&TestService{Name: "bench"}, container)
+	Inject(&TestStructWithDependency{}, container)
+	return container
+}
+
+// BenchmarkGetPointer measures cached resolution of a concrete pointer type
+func BenchmarkGetPointer(b *testing.B) {
+	container := newBenchmarkContainer()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Get[*TestStruct](container)
+	}
+}
+
+// BenchmarkGetPointerUncached measures the lookup Get performs on a cache miss
+func BenchmarkGetPointerUncached(b *testing.B) {
+	container := newBenchmarkContainer()
+	targetType := reflect.TypeOf((**TestStruct)(nil)).Elem()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		accessor, _ := findService[*TestStruct](container, targetType)
+		accessor()
+	}
+}
+
+// BenchmarkGetInterface measures cached resolution through an interface
+func BenchmarkGetInterface(b *testing.B) {
+	container := newBenchmarkContainer()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Get[TestInterface](container)
+	}
+}
+
+// BenchmarkGetInterfaceUncached measures the interface scan Get performs on a cache miss
+func BenchmarkGetInterfaceUncached(b *testing.B) {
+	container := newBenchmarkContainer()
+	targetType := reflect.TypeOf((*TestInterface)(nil)).Elem()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		accessor, _ := findService[TestInterface](container, targetType)
+		accessor()
+	}
+}
+
+// BenchmarkGetParallel measures cached resolution under concurrent access
+func BenchmarkGetParallel(b *testing.B) {
+	container := newBenchmarkContainer()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			Get[TestInterface](container)
+		}
+	})
+}
+
+// TestInjectKeepsSameNamedTypesApart tests that types with the same name in different packages do not collide
+func TestInjectKeepsSameNamedTypesApart(t *testing.T) {
+	container := NewContainer()
+	textTemplate := texttemplate.New("text")
+	htmlTemplate := htmltemplate.New("html")
+	Inject(textTemplate, container)
+	Inject(htmlTemplate, container)
+
+	if container.Count() != 2 {
+		t.Fatalf("Expected 2 services, got %d", container.Count())
+	}
+	if Get[*texttemplate.Template](container) != textTemplate {
+		t.Error("Expected the text template")
+	}
+	if Get[*htmltemplate.Template](container) != htmlTemplate {
+		t.Error("Expected the html template")
+	}
+	if _, found := container.Resolve("*text/template.Template"); !found {
+		t.Error("Expected the service under its canonical key")
+	}
+}
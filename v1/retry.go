@@ -0,0 +1,51 @@
+package sioc
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// retryPolicy is how many times a failing Init method is called, and how long to wait
+// before the second attempt. The wait doubles after every further failure.
+type retryPolicy struct {
+	attempts int
+	backoff  time.Duration
+}
+
+// maxRetryDelay caps the doubled wait between attempts, unless the backoff itself is longer.
+const maxRetryDelay = time.Minute
+
+// delay returns the wait after the given failed attempt, counted from 1.
+func (rp retryPolicy) delay(attempt int) time.Duration {
+	limit := max(maxRetryDelay, rp.backoff)
+	delay := rp.backoff
+	for ; attempt > 1 && delay < limit; attempt-- {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+// InitAttemptError is the error of one failed call to a service's Init method. Hooks
+// receive one for every failed attempt, and Init gives up with the last one.
+type InitAttemptError struct {
+	ServiceType reflect.Type
+	Attempt     int
+	Attempts    int
+	Err         error
+}
+
+func (iae *InitAttemptError) Error() string {
+	return fmt.Sprintf("init of %v failed (attempt %d of %d): %v", iae.ServiceType, iae.Attempt, iae.Attempts, iae.Err)
+}
+
+func (iae *InitAttemptError) Unwrap() error {
+	return iae.Err
+}
+
+// callInit calls the Init method and returns the error it reports as its last result, if
+// any, or the PanicError of a panic.
+func callInit(serviceContainer ServiceContainer, serviceType reflect.Type, initializationMethod reflect.Value, methodParams []reflect.Value) (err error) {
+	defer recoverPanic(repanics(serviceContainer), serviceType, initStage, &err)
+	return returnedError(initializationMethod.Type(), initializationMethod.Call(methodParams))
+}
@@ -0,0 +1,158 @@
+package sioc
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var errBrokerDown = errors.New("broker not ready")
+
+type flakyBroker struct {
+	failures int
+	calls    int
+	client   *TestStruct
+}
+
+func (fb *flakyBroker) Init(client *TestStruct) error {
+	fb.calls++
+	if fb.calls <= fb.failures {
+		return errBrokerDown
+	}
+	fb.client = client
+	return nil
+}
+
+// TestInitRetriesFailingService tests that Init retries with backoff and reports every attempt
+func TestInitRetriesFailingService(t *testing.T) {
+	recorder := NewHookRecorder()
+	container := NewContainer(WithHooks(recorder))
+	Inject(&TestStruct{Value: "client"}, container)
+	broker := &flakyBroker{failures: 2}
+	Inject(broker, container, WithRetry(3, 5*time.Millisecond))
+
+	startedAt := time.Now()
+	Init(container)
+	if elapsed := time.Since(startedAt); elapsed < 15*time.Millisecond {
+		t.Errorf("Expected Init to wait 5ms then 10ms between attempts, took %v", elapsed)
+	}
+
+	if broker.calls != 3 || broker.client == nil {
+		t.Fatalf("Expected a successful third attempt, got %+v", broker)
+	}
+	brokerEvents := func(kind HookEventKind) []HookEvent {
+		var events []HookEvent
+		for _, event := range recorder.EventsOf(kind) {
+			if event.ServiceType == reflect.TypeOf(broker) {
+				events = append(events, event)
+			}
+		}
+		return events
+	}
+	if starts := brokerEvents(HookInitStart); len(starts) != 3 {
+		t.Errorf("Expected 3 init attempts, got %d", len(starts))
+	}
+	failures := brokerEvents(HookError)
+	if len(failures) != 2 {
+		t.Fatalf("Expected 2 failed attempts, got %d", len(failures))
+	}
+	var attemptErr *InitAttemptError
+	if !errors.As(failures[1].Err, &attemptErr) || attemptErr.Attempt != 2 || attemptErr.Attempts != 3 || !errors.Is(attemptErr, errBrokerDown) {
+		t.Errorf("Expected the second attempt error, got %v", failures[1].Err)
+	}
+	if done := brokerEvents(HookInitDone); len(done) != 1 {
+		t.Errorf("Expected a single init done event, got %d", len(done))
+	}
+}
+
+// TestInitGivesUpAfterLastAttempt tests that the last attempt's error is returned once retries run out
+func TestInitGivesUpAfterLastAttempt(t *testing.T) {
+	container := NewContainer()
+	Inject(&TestStruct{}, container)
+	broker := &flakyBroker{failures: 5}
+	Inject(broker, container, WithRetry(2, time.Millisecond))
+
+	var entry *serviceEntry
+	for _, candidate := range entriesOf(container) {
+		if unwrapService(candidate.instance) == broker {
+			entry = candidate
+		}
+	}
+	_, err := initializeEntry(container, entry, buildDependencyMap(container), true)
+
+	var attemptErr *InitAttemptError
+	if !errors.As(err, &attemptErr) || attemptErr.Attempt != 2 || broker.calls != 2 {
+		t.Errorf("Expected Init to give up after 2 attempts, got %v after %d calls", err, broker.calls)
+	}
+	if entry.isInitialized() {
+		t.Error("Expected the entry to stay uninitialized")
+	}
+}
+
+// TestInitWithoutRetryCallsOnce tests that services without a policy get a single attempt
+func TestInitWithoutRetryCallsOnce(t *testing.T) {
+	container := NewContainer()
+	Inject(&TestStruct{}, container)
+	broker := &flakyBroker{failures: 1}
+	Inject(broker, container)
+
+	for _, entry := range entriesOf(container) {
+		if unwrapService(entry.instance) == broker {
+			if _, err := initializeEntry(container, entry, buildDependencyMap(container), true); !errors.Is(err, errBrokerDown) {
+				t.Errorf("Expected the Init error, got %v", err)
+			}
+		}
+	}
+	if broker.calls != 1 {
+		t.Errorf("Expected a single attempt, got %d", broker.calls)
+	}
+}
+
+type countingProvider struct {
+	calls int
+}
+
+func (cp *countingProvider) Init() error {
+	cp.calls++
+	return errBrokerDown
+}
+
+func (cp *countingProvider) ProvideService() any {
+	return &providedClient{}
+}
+
+// TestLookupsDoNotRetryFailedProviders tests that lookups call a provider's Init once and then return its recorded error
+func TestLookupsDoNotRetryFailedProviders(t *testing.T) {
+	container := NewContainer()
+	provider := &countingProvider{}
+	Inject(provider, container, WithRetry(3, time.Hour))
+
+	for lookup := 0; lookup < 3; lookup++ {
+		err := Invoke(container, func(*providedClient) {})
+		var attemptErr *InitAttemptError
+		if !errors.As(err, &attemptErr) || attemptErr.Attempts != 1 || !errors.Is(err, errBrokerDown) {
+			t.Fatalf("Expected the recorded Init error, got %v", err)
+		}
+	}
+	if provider.calls != 1 {
+		t.Errorf("Expected a single Init call across lookups, got %d", provider.calls)
+	}
+}
+
+// TestRetryDelayIsCapped tests that the doubled wait stops growing instead of overflowing
+func TestRetryDelayIsCapped(t *testing.T) {
+	policy := retryPolicy{attempts: 100, backoff: 500 * time.Millisecond}
+	if delay := policy.delay(3); delay != 2*time.Second {
+		t.Errorf("Expected the wait to double, got %v", delay)
+	}
+	for _, attempt := range []int{8, 40, 70, 100} {
+		if delay := policy.delay(attempt); delay != maxRetryDelay {
+			t.Errorf("Expected attempt %d to wait %v, got %v", attempt, maxRetryDelay, delay)
+		}
+	}
+	long := retryPolicy{attempts: 3, backoff: 2 * time.Hour}
+	if delay := long.delay(3); delay != 2*time.Hour {
+		t.Errorf("Expected a longer backoff to be kept, got %v", delay)
+	}
+}
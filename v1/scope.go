@@ -0,0 +1,167 @@
+package sioc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Scope is a child container whose own registrations live until Close is called.
+// Lookups that miss the scope fall back to its parent until the scope is closed.
+type Scope interface {
+	ServiceContainer
+	Close() error
+}
+
+// scopeRegistry is a serviceRegistry that disposes its own services when closed.
+type scopeRegistry struct {
+	*serviceRegistry
+	closeOnce sync.Once
+	closeErr  error
+	// release runs once the scope is closed, such as returning a tenant overlay lease.
+	release func() error
+
+	// leaseMutex guards leases and references.
+	leaseMutex sync.Mutex
+	leases     []poolLease
+	// references are the instances resolved from the scope, by wrapper, released on Close.
+	references map[*serviceWrapper[any]]*serviceVersion[any]
+}
+
+// poolLease is a pooled service resolved from the scope and not released yet.
+type poolLease struct {
+	pool    *pooledService
+	service any
+}
+
+// lease records a pooled service resolved from the scope, so Close can return it.
+func (sc *scopeRegistry) lease(pool *pooledService, service any) {
+	sc.leaseMutex.Lock()
+	defer sc.leaseMutex.Unlock()
+	sc.leases = append(sc.leases, poolLease{pool: pool, service: service})
+}
+
+// forgetLease removes the lease of a released service. It reports false when the service
+// was already returned, so it is not put in the pool twice.
+func (sc *scopeRegistry) forgetLease(service any) bool {
+	sc.leaseMutex.Lock()
+	defer sc.leaseMutex.Unlock()
+	for index, lease := range sc.leases {
+		if lease.service == service {
+			sc.leases = append(sc.leases[:index], sc.leases[index+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// reference returns the instance of the wrapper the scope uses. The first lookup records a
+// reference to the current instance, and the scope keeps it until Close, even if it is swapped.
+func (sc *scopeRegistry) reference(wrapper *serviceWrapper[any]) any {
+	sc.leaseMutex.Lock()
+	defer sc.leaseMutex.Unlock()
+	if version, found := sc.references[wrapper]; found {
+		return version.instance
+	}
+	for {
+		version := wrapper.current.Load()
+		if version == nil {
+			return nil
+		}
+		// A version retired meanwhile is replaced in the wrapper; read it again.
+		if version.acquire() {
+			if sc.references == nil {
+				sc.references = make(map[*serviceWrapper[any]]*serviceVersion[any])
+			}
+			sc.references[wrapper] = version
+			return version.instance
+		}
+	}
+}
+
+// releaseReferences drops the scope's references, disposing swapped instances no longer used.
+func (sc *scopeRegistry) releaseReferences() {
+	sc.leaseMutex.Lock()
+	references := sc.references
+	sc.references = nil
+	sc.leaseMutex.Unlock()
+	for _, version := range references {
+		version.release()
+	}
+}
+
+// releaseLeases returns every pooled service still leased to the scope.
+func (sc *scopeRegistry) releaseLeases() {
+	sc.leaseMutex.Lock()
+	leases := sc.leases
+	sc.leases = nil
+	sc.leaseMutex.Unlock()
+	releaseLeases(leases)
+}
+
+// NewScope creates a child container of parent. Services registered in the scope get the
+// Scoped lifetime and shadow parent services with the same key; everything else resolves
+// from the parent. The scope shares the parent's logger and hooks.
+func NewScope(parent ServiceContainer) Scope {
+	return newScope(parent)
+}
+
+func newScope(parent ServiceContainer) *scopeRegistry {
+	registry := &serviceRegistry{
+		events:          *eventsOf(parent),
+		parent:          parent,
+		defaultLifetime: Scoped,
+		profiles:        activeProfiles(parent),
+		repanic:         repanics(parent),
+	}
+	return &scopeRegistry{serviceRegistry: registry}
+}
+
+// Close returns the pooled services resolved from the scope to their pools, releases the
+// instances it resolved so swapped ones can be disposed, then disposes every service
+// registered in the scope that implements io.Closer and removes them. Closing the parent's
+// services is left to the parent. Lookups on a closed scope no longer fall back to the
+// parent. Close is idempotent.
+func (sc *scopeRegistry) Close() error {
+	sc.closeOnce.Do(func() {
+		// Resolutions cached from the parent are dropped along with the fallback.
+		sc.closed.Store(true)
+		atomic.AddUint64(&sc.generation, 1)
+		sc.releaseLeases()
+		sc.releaseReferences()
+		var disposeErrors []error
+		for _, entry := range sc.entries() {
+			sc.services.Delete(entry.key)
+			atomic.AddUint64(&sc.generation, 1)
+			if err := disposeService(entry.instance); err != nil {
+				serviceType := reflect.TypeOf(unwrapService(entry.instance))
+				sc.events.failed("service dispose failed", serviceType, err)
+				disposeErrors = append(disposeErrors, fmt.Errorf("dispose %v: %w", serviceType, err))
+			}
+		}
+		if sc.release != nil {
+			disposeErrors = append(disposeErrors, sc.release())
+		}
+		sc.closeErr = errors.Join(disposeErrors...)
+	})
+	return sc.closeErr
+}
+
+// unwrapService returns the service held by a wrapper, or the value itself.
+func unwrapService(serviceInstance any) any {
+	if wrapper, ok := serviceInstance.(ServiceWrapper[any]); ok {
+		return wrapper.GetService()
+	}
+	return serviceInstance
+}
+
+// disposeService closes the service if it implements io.Closer.
+func disposeService(serviceInstance any) error {
+	if closer, ok := unwrapService(serviceInstance).(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
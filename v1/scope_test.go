@@ -0,0 +1,122 @@
+package sioc
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type closableService struct {
+	closed   int
+	closeErr error
+}
+
+func (cs *closableService) Close() error {
+	cs.closed++
+	return cs.closeErr
+}
+
+// TestScopeFallsBackToParent tests that scopes resolve parent services and shadow them with their own
+func TestScopeFallsBackToParent(t *testing.T) {
+	root := NewContainer()
+	Inject(&TestStruct{Value: "root"}, root)
+	Inject(&TestService{Name: "root"}, root)
+
+	scope := NewScope(root)
+	Inject(&TestService{Name: "scoped"}, scope)
+
+	if retrieved := Get[*TestStruct](scope); retrieved.Value != "root" {
+		t.Errorf("Expected parent service 'root', got %v", retrieved.Value)
+	}
+	if retrieved := Get[*TestService](scope); retrieved.Name != "scoped" {
+		t.Errorf("Expected scoped service to shadow parent, got %v", retrieved.Name)
+	}
+	if retrieved := Get[*TestService](root); retrieved.Name != "root" {
+		t.Errorf("Parent should not see scoped registrations, got %v", retrieved.Name)
+	}
+	if scope.Count() != 2 {
+		t.Errorf("Expected scope to see 2 services, got %d", scope.Count())
+	}
+
+	for _, info := range Describe(scope) {
+		if info.Lifetime != Scoped {
+			t.Errorf("Expected scoped lifetime for %s, got %s", info.Key, info.Lifetime)
+		}
+	}
+}
+
+// TestScopeSeesParentRegistrations tests that cached scope resolutions are invalidated by the parent
+func TestScopeSeesParentRegistrations(t *testing.T) {
+	root := NewContainer()
+	Inject(&TestStruct{Value: "first"}, root)
+	scope := NewScope(root)
+
+	if retrieved := Get[*TestStruct](scope); retrieved.Value != "first" {
+		t.Fatalf("Expected 'first', got %v", retrieved.Value)
+	}
+
+	Inject(&TestStruct{Value: "second"}, root)
+	if retrieved := Get[*TestStruct](scope); retrieved.Value != "second" {
+		t.Errorf("Expected parent re-registration to be visible, got %v", retrieved.Value)
+	}
+}
+
+// TestScopeInitUsesParentDependencies tests that scoped services can depend on parent services
+func TestScopeInitUsesParentDependencies(t *testing.T) {
+	root := NewContainer()
+	Inject(&TestStruct{Value: "root"}, root)
+
+	scope := NewScope(root)
+	dependent := &TestStructWithDependency{}
+	Inject(dependent, scope)
+	Init(scope)
+
+	if !dependent.initialized || dependent.Dependency == nil || dependent.Dependency.Value != "root" {
+		t.Errorf("Expected scoped service to receive the parent dependency, got %+v", dependent)
+	}
+}
+
+// TestScopeCloseDisposesOwnServices tests that Close disposes scoped services only, once
+func TestScopeCloseDisposesOwnServices(t *testing.T) {
+	root := NewContainer()
+	rootService := &closableService{}
+	Inject(rootService, root)
+
+	scope := NewScope(root)
+	failure := errors.New("close failed")
+	scopedService := &closableService{closeErr: failure}
+	InjectAs[context.Context](context.Background(), scope)
+	Inject(scopedService, scope)
+
+	if err := scope.Close(); !errors.Is(err, failure) {
+		t.Errorf("Expected dispose error to be returned, got %v", err)
+	}
+	if err := scope.Close(); !errors.Is(err, failure) {
+		t.Errorf("Expected repeated Close to return the same error, got %v", err)
+	}
+
+	if scopedService.closed != 1 {
+		t.Errorf("Expected scoped service to be closed once, got %d", scopedService.closed)
+	}
+	if rootService.closed != 0 {
+		t.Error("Parent services must not be disposed by the scope")
+	}
+	if err := Invoke(scope, func(*closableService) {}); !errors.Is(err, ErrDependencyNotFound) {
+		t.Errorf("Expected the closed scope not to fall back to the parent service, got %v", err)
+	}
+}
+
+// TestInjectAsRegistersUnderInterface tests registration under an interface type
+func TestInjectAsRegistersUnderInterface(t *testing.T) {
+	container := NewContainer()
+	ctx := context.WithValue(context.Background(), containerContextKey{}, "value")
+
+	InjectAs[context.Context](ctx, container)
+
+	if retrieved := Get[context.Context](container); retrieved != ctx {
+		t.Errorf("Expected the registered context, got %v", retrieved)
+	}
+	if _, found := container.Resolve("context.Context"); !found {
+		t.Error("Expected the service to be registered under its interface name")
+	}
+}
@@ -0,0 +1,57 @@
+// Package siocgrpc opens sioc v1 scopes for gRPC calls.
+package siocgrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	sioc "github.com/sergiodii/sioc/v1"
+)
+
+// UnaryServerInterceptor creates a sioc.Scope of root for every unary call. The scope is
+// stored in the call context, retrievable with sioc.FromContext, and has the call's
+// context.Context and incoming metadata.MD registered as scoped services. The scope is
+// closed when the handler returns.
+func UnaryServerInterceptor(root sioc.ServiceContainer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		scope, ctx := openScope(ctx, root)
+		defer scope.Close()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor creates a sioc.Scope of root for every streaming call, the same
+// way UnaryServerInterceptor does. The handler receives a stream whose Context carries the scope.
+func StreamServerInterceptor(root sioc.ServiceContainer) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		scope, ctx := openScope(stream.Context(), root)
+		defer scope.Close()
+		return handler(srv, &scopedServerStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// openScope creates the per-call scope and registers the call's context and metadata in it.
+func openScope(ctx context.Context, root sioc.ServiceContainer) (sioc.Scope, context.Context) {
+	scope := sioc.NewScope(root)
+	ctx = sioc.NewContext(ctx, scope)
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		md = metadata.MD{}
+	}
+	sioc.InjectAs[context.Context](ctx, scope)
+	sioc.InjectAs[metadata.MD](md, scope)
+	return scope, ctx
+}
+
+// scopedServerStream overrides the stream context with the one carrying the scope.
+type scopedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *scopedServerStream) Context() context.Context {
+	return ss.ctx
+}
@@ -0,0 +1,159 @@
+package siocgrpc
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	sioc "github.com/sergiodii/sioc/v1"
+)
+
+type tenantDirectory struct {
+	prefix string
+}
+
+type callResource struct {
+	closed *atomic.Int32
+}
+
+func (cr *callResource) Close() error {
+	cr.closed.Add(1)
+	return nil
+}
+
+// healthServer resolves per-call services from the scope opened by the interceptors.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	closed atomic.Int32
+}
+
+func (hs *healthServer) serviceFor(ctx context.Context) string {
+	scope, ok := sioc.FromContext(ctx)
+	if !ok {
+		return "no scope"
+	}
+	if sioc.Get[context.Context](scope) != ctx {
+		return "wrong context"
+	}
+	sioc.Inject(&callResource{closed: &hs.closed}, scope)
+
+	tenants := sioc.Get[metadata.MD](scope).Get("x-tenant")
+	if len(tenants) == 0 {
+		return "no tenant"
+	}
+	return sioc.Get[*tenantDirectory](scope).prefix + tenants[0]
+}
+
+func (hs *healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if hs.serviceFor(ctx) == "tenant-"+req.Service {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	return &healthpb.HealthCheckResponse{Status: status}, nil
+}
+
+func (hs *healthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if hs.serviceFor(stream.Context()) == "tenant-"+req.Service {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	return stream.Send(&healthpb.HealthCheckResponse{Status: status})
+}
+
+func startServer(t *testing.T, root sioc.ServiceContainer) (healthpb.HealthClient, *healthServer) {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(root)),
+		grpc.StreamInterceptor(StreamServerInterceptor(root)),
+	)
+	service := &healthServer{}
+	healthpb.RegisterHealthServer(server, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	connection, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { connection.Close() })
+	return healthpb.NewHealthClient(connection), service
+}
+
+func newRoot() sioc.ServiceContainer {
+	root := sioc.NewContainer()
+	sioc.Inject(&tenantDirectory{prefix: "tenant-"}, root)
+	return root
+}
+
+// TestUnaryInterceptorOpensScope tests that unary handlers resolve call-scoped services
+func TestUnaryInterceptorOpensScope(t *testing.T) {
+	root := newRoot()
+	client, service := startServer(t, root)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant", "acme")
+
+	response, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "acme"})
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if response.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected handler to resolve call metadata from the scope, got %v", response.Status)
+	}
+	if service.closed.Load() != 1 {
+		t.Errorf("Expected the call scope to be closed, got %d closes", service.closed.Load())
+	}
+	if root.Count() != 1 {
+		t.Errorf("Expected root container to be untouched, got %d services", root.Count())
+	}
+}
+
+// TestStreamInterceptorOpensScope tests that streaming handlers resolve call-scoped services
+func TestStreamInterceptorOpensScope(t *testing.T) {
+	client, service := startServer(t, newRoot())
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant", "acme")
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "acme"})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	response, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if response.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected handler to resolve call metadata from the scope, got %v", response.Status)
+	}
+
+	// Wait for the end of the stream, after which the scope has been closed
+	if _, err := stream.Recv(); err == nil {
+		t.Fatal("Expected the stream to end")
+	}
+	if service.closed.Load() != 1 {
+		t.Errorf("Expected the call scope to be closed, got %d closes", service.closed.Load())
+	}
+}
+
+// TestUnaryInterceptorWithoutMetadata tests calls without incoming metadata
+func TestUnaryInterceptorWithoutMetadata(t *testing.T) {
+	client, _ := startServer(t, newRoot())
+
+	response, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "acme"})
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if response.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Expected NOT_SERVING without tenant metadata, got %v", response.Status)
+	}
+}
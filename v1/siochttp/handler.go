@@ -0,0 +1,83 @@
+// Package siochttp exposes sioc v1 containers over net/http.
+package siochttp
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+
+	sioc "github.com/sergiodii/sioc/v1"
+)
+
+// serviceView is the JSON/HTML representation of a registered service.
+type serviceView struct {
+	sioc.ServiceInfo
+	InitDuration string `json:"initDuration"`
+}
+
+// containerView is the document served by Handler.
+type containerView struct {
+	Count    int           `json:"count"`
+	Services []serviceView `json:"services"`
+}
+
+var containerTemplate = template.Must(template.New("container").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>sioc container</title></head>
+<body>
+<h1>sioc container ({{.Count}} services)</h1>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Key</th><th>Type</th><th>Lifetime</th><th>Init</th><th>Initialized</th><th>Init duration</th><th>Dependencies</th><th>Pool</th></tr>
+{{range .Services}}<tr>
+<td>{{.Key}}</td><td><code>{{.Type}}</code></td><td>{{.Lifetime}}</td><td>{{.HasInit}}</td><td>{{.Initialized}}</td><td>{{.InitDuration}}</td>
+<td>{{range .Dependencies}}<code>{{.}}</code><br>{{end}}</td>
+<td>{{with .Pool}}hits {{.Hits}}, misses {{.Misses}}, outstanding {{.Outstanding}}{{end}}</td>
+</tr>{{end}}
+</table>
+</body>
+</html>
+`))
+
+// Handler returns an http.Handler that lists the services registered in the container,
+// with their concrete types, lifetimes, init status, init duration, dependency edges and,
+// for pooled services, pool statistics.
+// It serves JSON by default and HTML when requested with ?format=html or an Accept header
+// preferring text/html. It is meant for internal admin ports only.
+func Handler(serviceContainer sioc.ServiceContainer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		view := buildContainerView(serviceContainer)
+		if wantsHTML(r) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			containerTemplate.Execute(w, view)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(view)
+	})
+}
+
+func buildContainerView(serviceContainer sioc.ServiceContainer) containerView {
+	services := sioc.Describe(serviceContainer)
+	view := containerView{Count: len(services), Services: make([]serviceView, 0, len(services))}
+	for _, info := range services {
+		view.Services = append(view.Services, serviceView{ServiceInfo: info, InitDuration: info.InitDuration.String()})
+	}
+	return view
+}
+
+func wantsHTML(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "html"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
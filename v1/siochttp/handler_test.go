@@ -0,0 +1,108 @@
+package siochttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sioc "github.com/sergiodii/sioc/v1"
+)
+
+type repository struct{}
+
+type userService struct {
+	repository *repository
+}
+
+func (us *userService) Init(repository *repository) {
+	us.repository = repository
+}
+
+func newTestContainer() sioc.ServiceContainer {
+	container := sioc.NewContainer()
+	sioc.Inject(&repository{}, container)
+	sioc.Inject(&userService{}, container)
+	sioc.Init(container)
+	return container
+}
+
+// TestHandlerServesJSON tests that the handler lists services with their init state as JSON
+func TestHandlerServesJSON(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	Handler(newTestContainer()).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/sioc", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected JSON content type, got %s", contentType)
+	}
+
+	var view struct {
+		Count    int `json:"count"`
+		Services []struct {
+			Type         string   `json:"type"`
+			Lifetime     string   `json:"lifetime"`
+			Initialized  bool     `json:"initialized"`
+			InitDuration string   `json:"initDuration"`
+			Dependencies []string `json:"dependencies"`
+		} `json:"services"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&view); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	if view.Count != 2 || len(view.Services) != 2 {
+		t.Fatalf("Expected 2 services, got %d", view.Count)
+	}
+	for _, service := range view.Services {
+		if service.Lifetime != string(sioc.Singleton) {
+			t.Errorf("Expected singleton lifetime, got %s", service.Lifetime)
+		}
+		if service.Type != "*siochttp.userService" {
+			continue
+		}
+		if !service.Initialized || service.InitDuration == "" {
+			t.Errorf("Expected userService to be initialized with a duration, got %+v", service)
+		}
+		if len(service.Dependencies) != 1 || service.Dependencies[0] != "*siochttp.repository" {
+			t.Errorf("Expected dependency edge to *siochttp.repository, got %v", service.Dependencies)
+		}
+	}
+}
+
+// TestHandlerServesHTML tests that HTML is served when requested
+func TestHandlerServesHTML(t *testing.T) {
+	handler := Handler(newTestContainer())
+
+	for _, request := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/debug/sioc?format=html", nil),
+		func() *http.Request {
+			request := httptest.NewRequest(http.MethodGet, "/debug/sioc", nil)
+			request.Header.Set("Accept", "text/html,application/xhtml+xml")
+			return request
+		}(),
+	} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/html") {
+			t.Errorf("Expected HTML content type, got %s", recorder.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(recorder.Body.String(), "*siochttp.userService") {
+			t.Error("Expected HTML to list the registered services")
+		}
+	}
+}
+
+// TestHandlerRejectsWrites tests that only read methods are allowed
+func TestHandlerRejectsWrites(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	Handler(newTestContainer()).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/debug/sioc", nil))
+
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,51 @@
+package siochttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	sioc "github.com/sergiodii/sioc/v1"
+)
+
+// HealthHandler returns an http.Handler serving the container's sioc.Health report. Requests
+// to a path ending in /healthz only run the health checks and answer 200 when they all
+// passed, and requests to a path ending in /readyz run every check and answer 200 when they
+// all passed; both answer 503 otherwise, with the report as JSON. Other paths are not found.
+// Mount it on both paths:
+//
+//	health := siochttp.HealthHandler(container)
+//	mux.Handle("/healthz", health)
+//	mux.Handle("/readyz", health)
+func HealthHandler(serviceContainer sioc.ServiceContainer, options ...sioc.HealthOption) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var passed func(sioc.HealthReport) bool
+		checkOptions := options
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/healthz"):
+			// Readiness checks can be slow, and liveness probes have short timeouts.
+			checkOptions = append(options[:len(options):len(options)], sioc.CheckKinds(sioc.HealthCheckKind))
+			passed = func(report sioc.HealthReport) bool { return report.Healthy }
+		case strings.HasSuffix(r.URL.Path, "/readyz"):
+			passed = func(report sioc.HealthReport) bool { return report.Ready }
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		report := sioc.Health(r.Context(), serviceContainer, checkOptions...)
+		w.Header().Set("Content-Type", "application/json")
+		if !passed(report) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	})
+}
@@ -0,0 +1,68 @@
+package siochttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sioc "github.com/sergiodii/sioc/v1"
+)
+
+type database struct{}
+
+func (*database) HealthCheck(context.Context) error { return nil }
+
+type migrations struct{}
+
+func (*migrations) Ready(context.Context) error { return errors.New("migrations pending") }
+
+// TestHealthHandlerStatus tests that /healthz and /readyz answer from the health report
+func TestHealthHandlerStatus(t *testing.T) {
+	container := sioc.NewContainer()
+	sioc.Inject(&database{}, container)
+	sioc.Inject(&migrations{}, container)
+	handler := HealthHandler(container)
+
+	for path, status := range map[string]int{
+		"/healthz":       http.StatusOK,
+		"/admin/readyz":  http.StatusServiceUnavailable,
+		"/somethingelse": http.StatusNotFound,
+	} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != status {
+			t.Errorf("Expected status %d for %s, got %d", status, path, recorder.Code)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var report sioc.HealthReport
+	if err := json.NewDecoder(recorder.Body).Decode(&report); err != nil {
+		t.Fatalf("Expected a JSON report, got %v", err)
+	}
+	if !report.Healthy || report.Ready || len(report.Checks) != 2 {
+		t.Errorf("Expected a healthy, unready report with 2 checks, got %+v", report)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if err := json.NewDecoder(recorder.Body).Decode(&report); err != nil {
+		t.Fatalf("Expected a JSON report, got %v", err)
+	}
+	if len(report.Checks) != 1 || report.Checks[0].Kind != sioc.HealthCheckKind {
+		t.Errorf("Expected /healthz to run only the health check, got %+v", report.Checks)
+	}
+}
+
+// TestHealthHandlerRejectsPost tests that only GET and HEAD are served
+func TestHealthHandlerRejectsPost(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	HealthHandler(sioc.NewContainer()).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/healthz", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", recorder.Code)
+	}
+}
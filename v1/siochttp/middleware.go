@@ -0,0 +1,27 @@
+package siochttp
+
+import (
+	"context"
+	"net/http"
+
+	sioc "github.com/sergiodii/sioc/v1"
+)
+
+// Middleware creates a sioc.Scope of root for every request. The scope is stored in the
+// request context, retrievable with sioc.FromContext, and has the *http.Request and its
+// context.Context registered as scoped services. The scope is closed when the handler returns.
+func Middleware(root sioc.ServiceContainer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope := sioc.NewScope(root)
+			defer scope.Close()
+
+			ctx := sioc.NewContext(r.Context(), scope)
+			r = r.WithContext(ctx)
+			sioc.InjectAs[context.Context](ctx, scope)
+			sioc.Inject(r, scope)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
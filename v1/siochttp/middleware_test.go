@@ -0,0 +1,64 @@
+package siochttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sioc "github.com/sergiodii/sioc/v1"
+)
+
+type requestLogger struct {
+	path   string
+	closed bool
+}
+
+func (rl *requestLogger) Init(r *http.Request) {
+	rl.path = r.URL.Path
+}
+
+func (rl *requestLogger) Close() error {
+	rl.closed = true
+	return nil
+}
+
+// TestMiddlewareCreatesRequestScope tests that handlers can resolve request-scoped services
+func TestMiddlewareCreatesRequestScope(t *testing.T) {
+	root := sioc.NewContainer()
+	sioc.Inject(&repository{}, root)
+
+	var scoped *requestLogger
+	handler := Middleware(root)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope, ok := sioc.FromContext(r.Context())
+		if !ok {
+			t.Fatal("Expected the request context to carry a scope")
+		}
+
+		if sioc.Get[*http.Request](scope) != r {
+			t.Error("Expected the current request to be registered in the scope")
+		}
+		if sioc.Get[context.Context](scope) != r.Context() {
+			t.Error("Expected the request context to be registered in the scope")
+		}
+		if sioc.Get[*repository](scope) == nil {
+			t.Error("Expected root services to resolve through the scope")
+		}
+
+		scoped = &requestLogger{}
+		sioc.Inject(scoped, scope)
+		sioc.Init(scope)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	if scoped == nil || scoped.path != "/users" {
+		t.Fatalf("Expected scoped service to be initialized with the request, got %+v", scoped)
+	}
+	if !scoped.closed {
+		t.Error("Expected scoped services to be disposed when the handler returns")
+	}
+	if root.Count() != 1 {
+		t.Errorf("Expected root container to be untouched, got %d services", root.Count())
+	}
+}
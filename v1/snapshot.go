@@ -0,0 +1,126 @@
+package sioc
+
+import (
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// ContainerSnapshot is the registration state of a container saved by Snapshot.
+type ContainerSnapshot struct {
+	registry *serviceRegistry
+	entries  []entrySnapshot
+	// released is set once Restore or Release dropped the references to the saved versions.
+	released atomic.Bool
+}
+
+// entrySnapshot is an entry together with the state Restore puts back. The snapshot holds a
+// reference to the saved version of a swappable service, so Swap does not dispose it.
+type entrySnapshot struct {
+	entry        *serviceEntry
+	version      *serviceVersion[any]
+	instance     any
+	initialized  bool
+	initDuration time.Duration
+	dependencies []reflect.Type
+	provider     ServiceProvider
+	retry        retryPolicy
+}
+
+// Snapshot saves the registrations of the container, the instance each one holds and its
+// init status, so Restore can roll back what a test changed. Instances swapped out since are
+// not disposed until the snapshot is restored or released. It returns nil for containers other
+// than the built-in ones. Scopes save their own registrations only.
+func Snapshot(serviceContainer ServiceContainer) *ContainerSnapshot {
+	registry, ok := registryOf(serviceContainer)
+	if !ok {
+		return nil
+	}
+	snapshot := &ContainerSnapshot{registry: registry}
+	for _, entry := range registry.entries() {
+		entry.mutex.Lock()
+		saved := entrySnapshot{
+			entry:        entry,
+			instance:     unwrapService(entry.instance),
+			initialized:  entry.initialized,
+			initDuration: entry.initDuration,
+			dependencies: entry.dependencies,
+			provider:     entry.provider,
+			retry:        entry.retry,
+		}
+		entry.mutex.Unlock()
+		if wrapper, ok := entry.instance.(*serviceWrapper[any]); ok {
+			saved.version = acquireCurrent(wrapper)
+		}
+		snapshot.entries = append(snapshot.entries, saved)
+	}
+	return snapshot
+}
+
+// acquireCurrent records a reference to the wrapper's current version and returns it.
+func acquireCurrent(wrapper *serviceWrapper[any]) *serviceVersion[any] {
+	for {
+		version := wrapper.current.Load()
+		// A version retired meanwhile is replaced in the wrapper; read it again.
+		if version == nil || version.acquire() {
+			return version
+		}
+	}
+}
+
+// Release drops the snapshot's references to the saved instances, so the ones swapped out
+// since can be disposed, when the snapshot is discarded without Restore. Release is idempotent.
+func (cs *ContainerSnapshot) Release() {
+	if cs == nil || !cs.released.CompareAndSwap(false, true) {
+		return
+	}
+	for _, saved := range cs.entries {
+		if saved.version != nil {
+			saved.version.release()
+		}
+	}
+}
+
+// Restore rolls the container back to the snapshot: services registered since are removed,
+// replaced and swapped services get their saved instance back, and init status is reset, so
+// Init runs again for services initialized since. The services' own fields are not restored.
+// Instances swapped in since are disposed like Swap disposes the instances it replaces. A
+// snapshot is restored once, which releases it. Restore must not run concurrently with
+// lookups on the container.
+func Restore(serviceContainer ServiceContainer, snapshot *ContainerSnapshot) error {
+	registry, ok := registryOf(serviceContainer)
+	if !ok || snapshot == nil {
+		return errors.New("sioc: no snapshot to restore for this container")
+	}
+	if snapshot.registry != registry {
+		return errors.New("sioc: the snapshot was taken from another container")
+	}
+	if snapshot.released.Load() {
+		return errors.New("sioc: the snapshot was already restored or released")
+	}
+	defer snapshot.Release()
+
+	registry.services.Range(func(serviceKey, _ any) bool {
+		registry.services.Delete(serviceKey)
+		return true
+	})
+	for _, saved := range snapshot.entries {
+		entry := saved.entry
+		entry.mutex.Lock()
+		entry.initialized = saved.initialized
+		entry.initDuration = saved.initDuration
+		entry.dependencies = saved.dependencies
+		entry.provider = saved.provider
+		entry.retry = saved.retry
+		entry.mutex.Unlock()
+		// Swap may have retired the saved version; the snapshot's reference kept it from disposal.
+		if wrapper, ok := entry.instance.(*serviceWrapper[any]); ok && saved.version != nil && wrapper.current.Load() != saved.version {
+			saved.version.reinstate()
+			retireVersion(&registry.events, wrapper.current.Swap(saved.version), saved.instance)
+		}
+		registry.services.Store(entry.key, entry)
+	}
+	atomic.AddUint64(&registry.generation, 1)
+	return nil
+}
@@ -0,0 +1,99 @@
+package sioc
+
+import "testing"
+
+// TestRestoreRollsBackRegistrations tests that Restore removes, restores and re-initializes services
+func TestRestoreRollsBackRegistrations(t *testing.T) {
+	container := NewContainer()
+	original := &TestStruct{Value: "original"}
+	Inject(original, container)
+	dependent := &TestStructWithDependency{}
+	Inject(dependent, container)
+	snapshot := Snapshot(container)
+
+	Inject(&TestStruct{Value: "override"}, container)
+	Inject(&TestService{Name: "extra"}, container)
+	Init(container)
+	if Get[*TestStruct](container).Value != "override" || container.Count() != 3 {
+		t.Fatal("Expected the test changes to be visible before Restore")
+	}
+
+	if err := Restore(container, snapshot); err != nil {
+		t.Fatalf("Expected Restore to succeed, got %v", err)
+	}
+	if Get[*TestStruct](container) != original {
+		t.Error("Expected the overridden service to be restored")
+	}
+	if container.Count() != 2 {
+		t.Errorf("Expected 2 services after Restore, got %d", container.Count())
+	}
+	for _, info := range Describe(container) {
+		if info.Initialized {
+			t.Errorf("Expected %s to be uninitialized again", info.Key)
+		}
+	}
+
+	dependent.Dependency = nil
+	Init(container)
+	if dependent.Dependency != original {
+		t.Error("Expected Init to run again with the restored dependency")
+	}
+}
+
+// TestRestoreUndoesSwap tests that a swapped service gets its saved instance back
+func TestRestoreUndoesSwap(t *testing.T) {
+	container := NewContainer()
+	first := &featureConfig{name: "first"}
+	Inject(first, container)
+	snapshot := Snapshot(container)
+
+	second := &featureConfig{name: "second"}
+	Swap(container, second)
+	if first.closed != 0 {
+		t.Fatal("Expected the snapshot to keep the swapped instance from being disposed")
+	}
+	Restore(container, snapshot)
+	if Get[*featureConfig](container) != first {
+		t.Error("Expected the saved instance after Restore")
+	}
+	if first.closed != 0 {
+		t.Errorf("Expected the restored instance not to be disposed, got %d", first.closed)
+	}
+	if second.closed != 1 {
+		t.Errorf("Expected the swapped-in instance to be disposed by Restore, got %d", second.closed)
+	}
+
+	Swap(container, &featureConfig{name: "third"})
+	if first.closed != 1 {
+		t.Errorf("Expected a later Swap to dispose the restored instance, got %d", first.closed)
+	}
+	if err := Restore(container, snapshot); err == nil {
+		t.Error("Expected a restored snapshot not to be restored again")
+	}
+}
+
+// TestSnapshotReleaseDisposesSwapped tests that a released snapshot lets swapped instances be disposed
+func TestSnapshotReleaseDisposesSwapped(t *testing.T) {
+	container := NewContainer()
+	first := &featureConfig{name: "first"}
+	Inject(first, container)
+	snapshot := Snapshot(container)
+
+	Swap(container, &featureConfig{name: "second"})
+	snapshot.Release()
+	snapshot.Release()
+	if first.closed != 1 {
+		t.Errorf("Expected the swapped instance to be disposed once on Release, got %d", first.closed)
+	}
+}
+
+// TestRestoreRejectsForeignSnapshots tests that a snapshot only restores its own container
+func TestRestoreRejectsForeignSnapshots(t *testing.T) {
+	snapshot := Snapshot(NewContainer())
+	if err := Restore(NewContainer(), snapshot); err == nil {
+		t.Error("Expected an error for a snapshot of another container")
+	}
+	if err := Restore(NewContainer(), nil); err == nil {
+		t.Error("Expected an error for a nil snapshot")
+	}
+}
@@ -0,0 +1,252 @@
+package sioc
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sergiodii/sioc/extension/typekey"
+)
+
+// serviceVersion is one instance held by a wrapper. Swap retires the previous version, which
+// is disposed once no scope that resolved it is still open.
+type serviceVersion[T any] struct {
+	instance T
+
+	mutex      sync.Mutex
+	references int
+	retired    bool
+	dispose    func(T)
+}
+
+// acquire records a reference from a scope. It fails once the version is retired, so
+// scopes opened after a swap move on to the new version.
+func (sv *serviceVersion[T]) acquire() bool {
+	sv.mutex.Lock()
+	defer sv.mutex.Unlock()
+	if sv.retired {
+		return false
+	}
+	sv.references++
+	return true
+}
+
+// release drops a reference, disposing the version if it was the last one of a retired version.
+func (sv *serviceVersion[T]) release() {
+	sv.mutex.Lock()
+	sv.references--
+	dispose := sv.retired && sv.references == 0
+	sv.mutex.Unlock()
+	if dispose && sv.dispose != nil {
+		sv.dispose(sv.instance)
+	}
+}
+
+// reinstate makes a retired version current again, so it is no longer disposed. The caller
+// holds a reference, so it was not disposed yet.
+func (sv *serviceVersion[T]) reinstate() {
+	sv.mutex.Lock()
+	defer sv.mutex.Unlock()
+	sv.retired = false
+	sv.dispose = nil
+}
+
+// retire marks the version as replaced, disposing it right away if no scope references it.
+func (sv *serviceVersion[T]) retire(dispose func(T)) {
+	sv.mutex.Lock()
+	sv.retired = true
+	sv.dispose = dispose
+	unreferenced := sv.references == 0
+	sv.mutex.Unlock()
+	if unreferenced && dispose != nil {
+		dispose(sv.instance)
+	}
+}
+
+// Swap atomically replaces the service resolved as T with newService, in the container where
+// it is registered. Get, Live handles and Invoke see the new instance on their next access,
+// and Watch channels receive it. Scopes keep the instance they first resolved until they are
+// closed, and services that received the previous instance in Init keep it. The previous
+// instance is disposed, if it implements io.Closer, once every scope that resolved it is
+// closed. Pooled services cannot be swapped.
+func Swap[T any](serviceContainer ServiceContainer, newService T) error {
+	targetType := reflect.TypeOf((*T)(nil)).Elem()
+	if any(newService) == nil {
+		return fmt.Errorf("sioc: cannot swap %v for nil", targetType)
+	}
+	owner, entry, wrapper, err := swappableEntry(serviceContainer, targetType)
+	if err != nil {
+		return err
+	}
+
+	previous := wrapper.current.Swap(&serviceVersion[any]{instance: newService})
+	// Cached resolutions may assert the previous concrete type.
+	atomic.AddUint64(&owner.generation, 1)
+	events := eventsOf(serviceContainer)
+	events.swapped(targetType)
+	retireVersion(events, previous, newService)
+	entry.notifyWatchers(newService)
+	return nil
+}
+
+// retireVersion retires the replaced version, disposing its instance once unreferenced unless
+// the replacement is the same instance.
+func retireVersion(events *containerEvents, previous *serviceVersion[any], replacement any) {
+	if previous == nil {
+		return
+	}
+	var dispose func(any)
+	if !sameInstance(previous.instance, replacement) {
+		dispose = func(instance any) {
+			if err := disposeService(instance); err != nil {
+				events.failed("service dispose failed", reflect.TypeOf(instance), err)
+			}
+		}
+	}
+	previous.retire(dispose)
+}
+
+// Live is a handle to a service that resolves it on every access, so it follows Swap.
+// Hold a Live handle instead of the instance when the service may be replaced.
+type Live[T any] struct {
+	serviceContainer ServiceContainer
+}
+
+// NewLive returns a Live handle to the service resolved as T from the container.
+func NewLive[T any](serviceContainer ServiceContainer) Live[T] {
+	return Live[T]{serviceContainer: serviceContainer}
+}
+
+// Get returns the current instance of the service.
+func (l Live[T]) Get() T {
+	return Get[T](l.serviceContainer)
+}
+
+// Watch returns a channel that receives the new instance every time the service resolved
+// as T is swapped, and a function that stops the notifications and closes the channel.
+// Slow readers only see the latest instance. Watch fails like Get when T is not registered.
+func Watch[T any](serviceContainer ServiceContainer) (<-chan T, func()) {
+	targetType := reflect.TypeOf((*T)(nil)).Elem()
+	_, entry, _, err := swappableEntry(serviceContainer, targetType)
+	if err != nil {
+		eventsOf(serviceContainer).failed("service watch failed", targetType, err)
+		log.Fatalf("Watch of %v: %v", targetType, err)
+	}
+
+	changes := make(chan T, 1)
+	var mutex sync.Mutex
+	closed := false
+	watcherID := entry.addWatcher(func(newService any) {
+		service, ok := newService.(T)
+		if !ok {
+			return
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		if closed {
+			return
+		}
+		// Replace an unread instance, so the channel always holds the latest one.
+		select {
+		case <-changes:
+		default:
+		}
+		changes <- service
+	})
+
+	stop := func() {
+		entry.removeWatcher(watcherID)
+		mutex.Lock()
+		defer mutex.Unlock()
+		if !closed {
+			closed = true
+			close(changes)
+		}
+	}
+	return changes, stop
+}
+
+// swappableEntry finds the entry resolved as the target type, looking in the container and
+// then in its parents, and the registry that owns it.
+func swappableEntry(serviceContainer ServiceContainer, targetType reflect.Type) (*serviceRegistry, *serviceEntry, *serviceWrapper[any], error) {
+	registry, ok := registryOf(serviceContainer)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("sioc: %T does not support swapping services", serviceContainer)
+	}
+	owner, entry, found := findEntry(registry, targetType)
+	if !found {
+		return nil, nil, nil, fmt.Errorf("%w: %v", ErrDependencyNotFound, targetType)
+	}
+	wrapper, ok := entry.instance.(*serviceWrapper[any])
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("sioc: %s service %v cannot be swapped", entry.lifetime, targetType)
+	}
+	return owner, entry, wrapper, nil
+}
+
+// findEntry returns the entry registered under the target type's key, or else the first
+// whose service is assignable to it, together with the registry that owns it.
+func findEntry(registry *serviceRegistry, targetType reflect.Type) (*serviceRegistry, *serviceEntry, bool) {
+	serviceKey := typekey.Of(targetType)
+	for current := registry; current != nil; current = parentRegistryOf(current) {
+		if entry, found := current.entry(serviceKey); found {
+			return current, entry, true
+		}
+	}
+	for current := registry; current != nil; current = parentRegistryOf(current) {
+		for _, entry := range current.entries() {
+			serviceInstance := unwrapService(entry.instance)
+			if serviceInstance != nil && reflect.TypeOf(serviceInstance).AssignableTo(targetType) {
+				return current, entry, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// parentRegistryOf returns the registry of the scope's parent, or nil.
+func parentRegistryOf(registry *serviceRegistry) *serviceRegistry {
+	parent, ok := registryOf(registry.parent)
+	if !ok {
+		return nil
+	}
+	return parent
+}
+
+// sameInstance reports whether both values are the same comparable instance.
+func sameInstance(first, second any) bool {
+	firstType := reflect.TypeOf(first)
+	return firstType == reflect.TypeOf(second) && firstType.Comparable() && first == second
+}
+
+// addWatcher registers a function called with the new instance on every swap.
+func (se *serviceEntry) addWatcher(notify func(any)) uint64 {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	if se.watchers == nil {
+		se.watchers = make(map[uint64]func(any))
+	}
+	se.nextWatcher++
+	se.watchers[se.nextWatcher] = notify
+	return se.nextWatcher
+}
+
+func (se *serviceEntry) removeWatcher(watcherID uint64) {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+	delete(se.watchers, watcherID)
+}
+
+func (se *serviceEntry) notifyWatchers(newService any) {
+	se.mutex.Lock()
+	watchers := make([]func(any), 0, len(se.watchers))
+	for _, notify := range se.watchers {
+		watchers = append(watchers, notify)
+	}
+	se.mutex.Unlock()
+	for _, notify := range watchers {
+		notify(newService)
+	}
+}
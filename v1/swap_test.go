@@ -0,0 +1,170 @@
+package sioc
+
+import (
+	"sync"
+	"testing"
+)
+
+type featureConfig struct {
+	name   string
+	closed int
+}
+
+func (fc *featureConfig) Close() error {
+	fc.closed++
+	return nil
+}
+
+type featureFlags interface {
+	Name() string
+}
+
+func (fc *featureConfig) Name() string {
+	return fc.name
+}
+
+type featureConsumer struct {
+	config Live[*featureConfig]
+}
+
+// TestSwapReplacesSingleton tests that Get and Live handles see the swapped instance
+func TestSwapReplacesSingleton(t *testing.T) {
+	container := NewContainer()
+	first := &featureConfig{name: "first"}
+	Inject(first, container)
+
+	consumer := &featureConsumer{config: NewLive[*featureConfig](container)}
+	if consumer.config.Get() != first {
+		t.Fatal("Expected the live handle to resolve the registered instance")
+	}
+
+	second := &featureConfig{name: "second"}
+	if err := Swap(container, second); err != nil {
+		t.Fatalf("Expected Swap to succeed, got %v", err)
+	}
+	if Get[*featureConfig](container) != second || consumer.config.Get() != second {
+		t.Error("Expected Get and the live handle to resolve the new instance")
+	}
+	if first.closed != 1 {
+		t.Errorf("Expected the replaced instance to be disposed once, got %d", first.closed)
+	}
+	if container.Count() != 1 {
+		t.Errorf("Expected Swap to keep a single registration, got %d", container.Count())
+	}
+}
+
+// TestSwapThroughInterface tests that a service can be swapped for another implementation
+func TestSwapThroughInterface(t *testing.T) {
+	container := NewContainer()
+	InjectAs[featureFlags](&featureConfig{name: "first"}, container)
+	if name := Get[featureFlags](container).Name(); name != "first" {
+		t.Fatalf("Expected 'first', got %s", name)
+	}
+
+	if err := Swap[featureFlags](container, &featureConfig{name: "second"}); err != nil {
+		t.Fatalf("Expected Swap to succeed, got %v", err)
+	}
+	if name := Get[featureFlags](container).Name(); name != "second" {
+		t.Errorf("Expected 'second', got %s", name)
+	}
+}
+
+// TestSwapWaitsForScopes tests that a replaced instance is disposed when the last scope using it closes
+func TestSwapWaitsForScopes(t *testing.T) {
+	root := NewContainer()
+	first := &featureConfig{name: "first"}
+	Inject(first, root)
+
+	scope := NewScope(root)
+	if Get[*featureConfig](scope) != first {
+		t.Fatal("Expected the scope to resolve the registered instance")
+	}
+
+	second := &featureConfig{name: "second"}
+	if err := Swap(root, second); err != nil {
+		t.Fatalf("Expected Swap to succeed, got %v", err)
+	}
+	if Get[*featureConfig](scope) != first {
+		t.Error("Expected the open scope to keep the instance it resolved")
+	}
+	if first.closed != 0 {
+		t.Fatal("Expected the replaced instance to stay alive while the scope is open")
+	}
+
+	if newScope := NewScope(root); Get[*featureConfig](newScope) != second {
+		t.Error("Expected a new scope to resolve the new instance")
+	}
+	scope.Close()
+	if first.closed != 1 || second.closed != 0 {
+		t.Errorf("Expected only the replaced instance to be disposed, got %d and %d", first.closed, second.closed)
+	}
+}
+
+// TestWatchDeliversSwaps tests that watchers receive the latest instance and stop cleanly
+func TestWatchDeliversSwaps(t *testing.T) {
+	container := NewContainer()
+	Inject(&featureConfig{name: "first"}, container)
+
+	changes, stop := Watch[*featureConfig](container)
+	Swap(container, &featureConfig{name: "second"})
+	Swap(container, &featureConfig{name: "third"})
+
+	if latest := <-changes; latest.name != "third" {
+		t.Errorf("Expected the latest instance, got %s", latest.name)
+	}
+
+	stop()
+	stop()
+	if _, open := <-changes; open {
+		t.Error("Expected stop to close the channel")
+	}
+	if err := Swap(container, &featureConfig{name: "fourth"}); err != nil {
+		t.Errorf("Expected Swap after stop to succeed, got %v", err)
+	}
+}
+
+// TestSwapErrors tests that Swap rejects missing, pooled and nil services
+func TestSwapErrors(t *testing.T) {
+	container := NewContainer()
+	if err := Swap(container, &featureConfig{}); err == nil {
+		t.Error("Expected an error for an unregistered service")
+	}
+
+	InjectPooled(func() *pooledBuffer { return &pooledBuffer{} }, container)
+	if err := Swap(container, &pooledBuffer{}); err == nil {
+		t.Error("Expected an error for a pooled service")
+	}
+
+	InjectAs[featureFlags](&featureConfig{}, container)
+	if err := Swap[featureFlags](container, nil); err == nil {
+		t.Error("Expected an error when swapping for nil")
+	}
+}
+
+// TestSwapConcurrentWithGet tests that swaps and lookups can run at the same time
+func TestSwapConcurrentWithGet(t *testing.T) {
+	container := NewContainer()
+	Inject(&featureConfig{name: "initial"}, container)
+
+	var waitGroup sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		waitGroup.Add(2)
+		go func() {
+			defer waitGroup.Done()
+			for index := 0; index < 100; index++ {
+				Swap(container, &featureConfig{name: "swapped"})
+			}
+		}()
+		go func() {
+			defer waitGroup.Done()
+			scope := NewScope(container)
+			defer scope.Close()
+			for index := 0; index < 100; index++ {
+				if Get[*featureConfig](scope) == nil {
+					t.Error("Expected an instance")
+				}
+			}
+		}()
+	}
+	waitGroup.Wait()
+}
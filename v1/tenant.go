@@ -0,0 +1,248 @@
+package sioc
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// TenantID is registered in every tenant overlay, so tenant services can receive it in Init.
+type TenantID string
+
+// TenantModule registers the services specific to a tenant in its overlay. It runs once,
+// the first time the tenant is requested, and again if the overlay was evicted. Call Init
+// on the overlay from the module when its services have Init methods.
+type TenantModule func(tenantID string, tenant ServiceContainer) error
+
+// TenantContainer is a shared container that hands out one overlay per tenant.
+type TenantContainer interface {
+	ServiceContainer
+	// Tenant leases the tenant's overlay, building it with the tenant module on first use.
+	// The returned scope resolves the tenant's own services first and falls back to the
+	// shared ones. Close it when done: the overlay is not disposed while it is leased.
+	Tenant(tenantID string) (Scope, error)
+	// Evict removes the tenant's overlay and closes it once no lease holds it, disposing its
+	// services. The next Tenant call builds it again.
+	Evict(tenantID string) error
+	// CloseTenants evicts every overlay. The shared services are left alone.
+	CloseTenants() error
+}
+
+// TenantOption configures a TenantContainer.
+type TenantOption func(*tenantContainer)
+
+// MaxTenants bounds the number of live overlays. Building one more evicts the least recently
+// used overlay. Zero, the default, keeps every overlay.
+func MaxTenants(maxTenants int) TenantOption {
+	return func(tc *tenantContainer) {
+		if maxTenants > 0 {
+			tc.maxTenants = maxTenants
+		}
+	}
+}
+
+// TenantIdleTimeout evicts overlays that were not requested for the given duration. Idle
+// overlays are evicted by the next Tenant call.
+func TenantIdleTimeout(idleTimeout time.Duration) TenantOption {
+	return func(tc *tenantContainer) {
+		if idleTimeout > 0 {
+			tc.idleTimeout = idleTimeout
+		}
+	}
+}
+
+// tenantContainer keeps the overlays in least recently used order, most recent first.
+type tenantContainer struct {
+	ServiceContainer
+	module      TenantModule
+	maxTenants  int
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	mutex    sync.Mutex
+	overlays map[string]*list.Element
+	order    *list.List
+}
+
+// tenantOverlay is a tenant's overlay, built by the first request while the others wait.
+// references counts the container's own hold, dropped on eviction, and the open leases;
+// the overlay is disposed when it reaches zero. The container's mutex guards it.
+type tenantOverlay struct {
+	tenantID   string
+	lastUsed   time.Time
+	references int
+	built      chan struct{}
+	scope      Scope
+	err        error
+}
+
+// NewTenantContainer wraps the shared container, whose services every tenant falls back to,
+// and builds tenant overlays with the module. Overlays are scopes of the shared container:
+// closing one disposes the tenant's services that implement io.Closer. Tenant hands out a
+// lease, a scope of the overlay, so an overlay evicted while a request uses it is disposed
+// when the last lease is closed. Call Tenant on every request rather than keeping a lease.
+func NewTenantContainer(shared ServiceContainer, module TenantModule, options ...TenantOption) TenantContainer {
+	tc := &tenantContainer{
+		ServiceContainer: shared,
+		module:           module,
+		now:              time.Now,
+		overlays:         make(map[string]*list.Element),
+		order:            list.New(),
+	}
+	for _, option := range options {
+		option(tc)
+	}
+	return tc
+}
+
+// registry exposes the shared registry, so the package functions treat the tenant container
+// like the shared container.
+func (tc *tenantContainer) registry() *serviceRegistry {
+	registry, _ := registryOf(tc.ServiceContainer)
+	return registry
+}
+
+func (tc *tenantContainer) Tenant(tenantID string) (Scope, error) {
+	now := tc.now()
+	tc.mutex.Lock()
+	evicted := tc.evictIdle(now)
+	element, found := tc.overlays[tenantID]
+	if found {
+		tc.order.MoveToFront(element)
+	} else {
+		element = tc.order.PushFront(&tenantOverlay{tenantID: tenantID, references: 1, built: make(chan struct{})})
+		tc.overlays[tenantID] = element
+		if tc.maxTenants > 0 && tc.order.Len() > tc.maxTenants {
+			evicted = append(evicted, tc.remove(tc.order.Back()))
+		}
+	}
+	overlay := element.Value.(*tenantOverlay)
+	overlay.lastUsed = now
+	overlay.references++
+	tc.mutex.Unlock()
+
+	tc.dispose(evicted)
+	if !found {
+		tc.build(overlay)
+	}
+	<-overlay.built
+	if overlay.err != nil {
+		tc.release(overlay)
+		return nil, overlay.err
+	}
+	lease := newScope(overlay.scope)
+	lease.release = func() error {
+		return tc.release(overlay)
+	}
+	return lease, nil
+}
+
+// build runs the tenant module in a new overlay. A failed overlay is forgotten, so the
+// next request tries again.
+func (tc *tenantContainer) build(overlay *tenantOverlay) {
+	defer close(overlay.built)
+	scope := NewScope(tc.ServiceContainer)
+	InjectAs[TenantID](TenantID(overlay.tenantID), scope)
+	if err := tc.runModule(overlay.tenantID, scope); err != nil {
+		scope.Close()
+		overlay.err = fmt.Errorf("sioc: tenant %q: %w", overlay.tenantID, err)
+		loggerOf(tc.ServiceContainer).Error("tenant build failed", slog.String("tenant", overlay.tenantID), slog.Any("error", err))
+		tc.mutex.Lock()
+		if element, found := tc.overlays[overlay.tenantID]; found && element.Value == overlay {
+			tc.remove(element)
+		}
+		tc.mutex.Unlock()
+		return
+	}
+	overlay.scope = scope
+	loggerOf(tc.ServiceContainer).Debug("tenant built", slog.String("tenant", overlay.tenantID))
+}
+
+// runModule calls the tenant module, converting a panic into a PanicError.
+func (tc *tenantContainer) runModule(tenantID string, tenant ServiceContainer) (err error) {
+	defer recoverPanic(repanics(tc.ServiceContainer), reflect.TypeOf(tc.module), tenantStage, &err)
+	return tc.module(tenantID, tenant)
+}
+
+func (tc *tenantContainer) Evict(tenantID string) error {
+	tc.mutex.Lock()
+	var evicted []*tenantOverlay
+	if element, found := tc.overlays[tenantID]; found {
+		evicted = append(evicted, tc.remove(element))
+	}
+	tc.mutex.Unlock()
+	return tc.dispose(evicted)
+}
+
+func (tc *tenantContainer) CloseTenants() error {
+	tc.mutex.Lock()
+	var evicted []*tenantOverlay
+	for tc.order.Len() > 0 {
+		evicted = append(evicted, tc.remove(tc.order.Back()))
+	}
+	tc.mutex.Unlock()
+	return tc.dispose(evicted)
+}
+
+// release drops a reference to the overlay and disposes it when it was the last one.
+func (tc *tenantContainer) release(overlay *tenantOverlay) error {
+	tc.mutex.Lock()
+	overlay.references--
+	unused := overlay.references == 0
+	tc.mutex.Unlock()
+	if !unused {
+		return nil
+	}
+	return tc.dispose([]*tenantOverlay{overlay})
+}
+
+// evictIdle removes the overlays idle for longer than the idle timeout. The caller holds the mutex.
+func (tc *tenantContainer) evictIdle(now time.Time) []*tenantOverlay {
+	var evicted []*tenantOverlay
+	if tc.idleTimeout == 0 {
+		return evicted
+	}
+	for element := tc.order.Back(); element != nil; element = tc.order.Back() {
+		if now.Sub(element.Value.(*tenantOverlay).lastUsed) < tc.idleTimeout {
+			break
+		}
+		evicted = append(evicted, tc.remove(element))
+	}
+	return evicted
+}
+
+// remove forgets the overlay and drops the container's reference to it. The caller holds the
+// mutex and disposes it afterwards; it is returned only if no lease holds it.
+func (tc *tenantContainer) remove(element *list.Element) *tenantOverlay {
+	overlay := tc.order.Remove(element).(*tenantOverlay)
+	delete(tc.overlays, overlay.tenantID)
+	overlay.references--
+	if overlay.references > 0 {
+		return nil
+	}
+	return overlay
+}
+
+// dispose closes the overlays once they are built, outside the mutex.
+func (tc *tenantContainer) dispose(evicted []*tenantOverlay) error {
+	var disposeErrors []error
+	for _, overlay := range evicted {
+		if overlay == nil {
+			continue
+		}
+		<-overlay.built
+		if overlay.scope == nil {
+			continue
+		}
+		loggerOf(tc.ServiceContainer).Debug("tenant evicted", slog.String("tenant", overlay.tenantID))
+		if err := overlay.scope.Close(); err != nil {
+			loggerOf(tc.ServiceContainer).Error("tenant dispose failed", slog.String("tenant", overlay.tenantID), slog.Any("error", err))
+			disposeErrors = append(disposeErrors, fmt.Errorf("tenant %q: %w", overlay.tenantID, err))
+		}
+	}
+	return errors.Join(disposeErrors...)
+}
@@ -0,0 +1,217 @@
+package sioc
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type tenantDatabase struct {
+	tenant TenantID
+	closed int
+}
+
+func (td *tenantDatabase) Init(tenant TenantID) {
+	td.tenant = tenant
+}
+
+func (td *tenantDatabase) Close() error {
+	td.closed++
+	return nil
+}
+
+type brandingConfig struct {
+	Theme string
+}
+
+// newTestTenants builds a tenant container whose module registers a database per tenant
+// and counts how many overlays it built.
+func newTestTenants(options ...TenantOption) (TenantContainer, *int) {
+	shared := NewContainer()
+	Inject(&brandingConfig{Theme: "default"}, shared)
+	builds := 0
+	var mutex sync.Mutex
+	tenants := NewTenantContainer(shared, func(tenantID string, tenant ServiceContainer) error {
+		mutex.Lock()
+		builds++
+		mutex.Unlock()
+		if tenantID == "broken" {
+			return errors.New("no database for tenant")
+		}
+		Inject(&tenantDatabase{}, tenant)
+		if tenantID == "acme" {
+			Inject(&brandingConfig{Theme: "acme"}, tenant)
+		}
+		Init(tenant)
+		return nil
+	}, options...)
+	return tenants, &builds
+}
+
+// TestTenantOverlaysFallBackToShared tests that overlays resolve their own services before shared ones
+func TestTenantOverlaysFallBackToShared(t *testing.T) {
+	tenants, builds := newTestTenants()
+
+	acme, err := tenants.Tenant("acme")
+	if err != nil {
+		t.Fatalf("Expected the overlay to build, got %v", err)
+	}
+	defer acme.Close()
+	globex, _ := tenants.Tenant("globex")
+	defer globex.Close()
+
+	if Get[*tenantDatabase](acme).tenant != "acme" || Get[*tenantDatabase](globex).tenant != "globex" {
+		t.Error("Expected each tenant to get its own database")
+	}
+	if Get[*brandingConfig](acme).Theme != "acme" || Get[*brandingConfig](globex).Theme != "default" {
+		t.Error("Expected acme to override the branding and globex to use the shared one")
+	}
+	if Get[*brandingConfig](tenants).Theme != "default" {
+		t.Error("Expected the tenant container to resolve shared services")
+	}
+
+	again, _ := tenants.Tenant("acme")
+	defer again.Close()
+	if Get[*tenantDatabase](again) != Get[*tenantDatabase](acme) || *builds != 2 {
+		t.Errorf("Expected overlays to be built once, got %d builds", *builds)
+	}
+}
+
+// TestTenantLRUEviction tests that the least recently used overlay is evicted and disposed
+func TestTenantLRUEviction(t *testing.T) {
+	tenants, builds := newTestTenants(MaxTenants(2))
+
+	request := func(tenantID string) *tenantDatabase {
+		lease, _ := tenants.Tenant(tenantID)
+		defer lease.Close()
+		return Get[*tenantDatabase](lease)
+	}
+	firstDatabase := request("first")
+	secondDatabase := request("second")
+	request("first")
+	request("third")
+
+	if secondDatabase.closed != 1 || firstDatabase.closed != 0 {
+		t.Errorf("Expected the least recently used tenant to be disposed, got %d and %d", secondDatabase.closed, firstDatabase.closed)
+	}
+	if request("second") == secondDatabase || *builds != 4 {
+		t.Errorf("Expected the evicted tenant to be rebuilt, got %d builds", *builds)
+	}
+}
+
+// TestTenantEvictionWaitsForLeases tests that an overlay held across its eviction is disposed on the last Close
+func TestTenantEvictionWaitsForLeases(t *testing.T) {
+	tenants, _ := newTestTenants(MaxTenants(1))
+
+	held, _ := tenants.Tenant("held")
+	heldDatabase := Get[*tenantDatabase](held)
+	other, _ := tenants.Tenant("other")
+	defer other.Close()
+
+	if heldDatabase.closed != 0 {
+		t.Fatal("Expected a leased overlay not to be disposed on eviction")
+	}
+	if Get[*tenantDatabase](held) != heldDatabase || Get[*brandingConfig](held).Theme != "default" {
+		t.Error("Expected the held overlay to keep resolving its services")
+	}
+	if err := held.Close(); err != nil {
+		t.Fatalf("Expected the lease to close, got %v", err)
+	}
+	if heldDatabase.closed != 1 {
+		t.Errorf("Expected the evicted overlay to be disposed with its last lease, got %d", heldDatabase.closed)
+	}
+	if err := Invoke(held, func(*brandingConfig) {}); !errors.Is(err, ErrDependencyNotFound) {
+		t.Errorf("Expected lookups on the closed lease not to fall back to the shared container, got %v", err)
+	}
+}
+
+// TestTenantIdleEviction tests that overlays idle past the timeout are disposed on the next request
+func TestTenantIdleEviction(t *testing.T) {
+	tenants, _ := newTestTenants(TenantIdleTimeout(time.Minute))
+	now := time.Now()
+	tenants.(*tenantContainer).now = func() time.Time { return now }
+
+	request := func(tenantID string) *tenantDatabase {
+		lease, _ := tenants.Tenant(tenantID)
+		defer lease.Close()
+		return Get[*tenantDatabase](lease)
+	}
+	idleDatabase := request("idle")
+	now = now.Add(30 * time.Second)
+	activeDatabase := request("active")
+
+	now = now.Add(45 * time.Second)
+	request("active")
+	if idleDatabase.closed != 1 || activeDatabase.closed != 0 {
+		t.Errorf("Expected only the idle tenant to be disposed, got %d and %d", idleDatabase.closed, activeDatabase.closed)
+	}
+}
+
+// TestTenantBuildFailure tests that module errors are returned and the build is retried
+func TestTenantBuildFailure(t *testing.T) {
+	tenants, builds := newTestTenants()
+
+	if _, err := tenants.Tenant("broken"); err == nil {
+		t.Fatal("Expected the module error")
+	}
+	if _, err := tenants.Tenant("broken"); err == nil || *builds != 2 {
+		t.Errorf("Expected the failed tenant to be built again, got %d builds", *builds)
+	}
+
+	panicking := NewTenantContainer(NewContainer(), func(string, ServiceContainer) error { panic("module exploded") })
+	var panicErr *PanicError
+	if _, err := panicking.Tenant("acme"); !errors.As(err, &panicErr) || panicErr.Stage != "tenant module" {
+		t.Errorf("Expected a tenant module PanicError, got %v", err)
+	}
+}
+
+// TestTenantEvictAndClose tests explicit eviction and closing every overlay
+func TestTenantEvictAndClose(t *testing.T) {
+	tenants, _ := newTestTenants()
+	var databases []*tenantDatabase
+	for index := 0; index < 3; index++ {
+		lease, _ := tenants.Tenant(fmt.Sprintf("tenant-%d", index))
+		databases = append(databases, Get[*tenantDatabase](lease))
+		lease.Close()
+	}
+
+	tenants.Evict("tenant-0")
+	tenants.Evict("unknown")
+	if databases[0].closed != 1 || databases[1].closed != 0 {
+		t.Error("Expected Evict to dispose only the evicted tenant")
+	}
+	if err := tenants.CloseTenants(); err != nil {
+		t.Fatalf("Expected CloseTenants to succeed, got %v", err)
+	}
+	for index, database := range databases {
+		if database.closed != 1 {
+			t.Errorf("Expected tenant-%d to be disposed once, got %d", index, database.closed)
+		}
+	}
+}
+
+// TestTenantConcurrentBuild tests that concurrent requests share a single build
+func TestTenantConcurrentBuild(t *testing.T) {
+	tenants, builds := newTestTenants(MaxTenants(4))
+	var waitGroup sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		waitGroup.Add(1)
+		go func(worker int) {
+			defer waitGroup.Done()
+			for index := 0; index < 50; index++ {
+				lease, err := tenants.Tenant(fmt.Sprintf("tenant-%d", (worker+index)%3))
+				if err != nil || lease == nil {
+					t.Errorf("Expected an overlay, got %v", err)
+					continue
+				}
+				lease.Close()
+			}
+		}(worker)
+	}
+	waitGroup.Wait()
+	if *builds != 3 {
+		t.Errorf("Expected 3 builds, got %d", *builds)
+	}
+}
@@ -1,6 +1,9 @@
 package sioc
 
-// ServiceProvider is a marker interface for types that can provide services.
+import "github.com/sergiodii/sioc/extension/clone"
+
+// ServiceProvider is implemented by services that register other services. Inject registers
+// what ProvideService returns, once the provider's Init method has run if it has one.
 type ServiceProvider interface {
 	ProvideService() any
 }
@@ -13,5 +16,14 @@ type ServiceWrapper[T any] interface {
 	SetService(serviceInstance T) ServiceWrapper[T]
 }
 
+// Cloner is implemented by services that build their own new instances. Init parameters
+// following an InstanceCreationMode marker, and CreateNewService, use Clone instead of a
+// reflection-based deep copy.
+type Cloner = clone.Cloner
+
+// InstanceFactory is the v0 IInstanceFactory. Services implementing it build their new
+// instances with NewInstance, as with Cloner, so they behave the same on v0 and v1.
+type InstanceFactory = clone.InstanceFactory
+
 // Backward compatibility type aliases
 type Container = ServiceContainer
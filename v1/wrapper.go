@@ -1,15 +1,21 @@
 package sioc
 
 import (
+	"fmt"
 	"reflect"
+	"sync/atomic"
 
-	"github.com/sergiodii/sioc/extension/text"
+	"github.com/sergiodii/sioc/extension/clone"
+	"github.com/sergiodii/sioc/extension/typekey"
 )
 
 // serviceWrapper is a generic wrapper for service instances.
 type serviceWrapper[T any] struct {
-	serviceInstance T
-	serviceName     string
+	// current holds the service instance, replaced as a whole by Swap.
+	current atomic.Pointer[serviceVersion[T]]
+	// serviceName is the canonical key of the service's type.
+	serviceName string
+	serviceType reflect.Type
 }
 
 // NewServiceWrapper creates a new service wrapper for type T.
@@ -19,8 +25,9 @@ func NewServiceWrapper[T any]() ServiceWrapper[T] {
 
 // SetService sets the service instance and its name in the wrapper.
 func (sw *serviceWrapper[T]) SetService(serviceInstance T) ServiceWrapper[T] {
-	sw.serviceName = sw.sanitizeServiceName(reflect.TypeOf(serviceInstance).String())
-	sw.serviceInstance = serviceInstance
+	sw.serviceType = reflect.TypeOf(serviceInstance)
+	sw.serviceName = typekey.Of(sw.serviceType)
+	sw.current.Store(&serviceVersion[T]{instance: serviceInstance})
 	return sw
 }
 
@@ -31,7 +38,12 @@ func (sw *serviceWrapper[T]) addInstance(instance T) ServiceWrapper[T] {
 
 // GetService returns the stored service instance.
 func (sw *serviceWrapper[T]) GetService() T {
-	return sw.serviceInstance
+	version := sw.current.Load()
+	if version == nil {
+		var empty T
+		return empty
+	}
+	return version.instance
 }
 
 // Backward compatibility: getInstance is an alias for GetService.
@@ -39,11 +51,28 @@ func (sw *serviceWrapper[T]) getInstance() T {
 	return sw.GetService()
 }
 
-// CreateNewService returns a copy of the stored service instance (if possible).
+// CreateNewService returns a deep copy of the stored service instance that shares no pointers
+// with it. Services implementing Cloner copy themselves. It panics if the service cannot be
+// copied, for example because it holds a channel.
 func (sw *serviceWrapper[T]) CreateNewService() T {
-	newService := new(T)
-	*newService = sw.serviceInstance
-	return *newService
+	newService, err := sw.cloneService()
+	if err != nil {
+		panic(err)
+	}
+	return newService
+}
+
+// cloneService returns a deep copy of the stored service instance.
+func (sw *serviceWrapper[T]) cloneService() (T, error) {
+	copied, err := clone.Deep(sw.GetService())
+	if err != nil || copied == nil {
+		var empty T
+		if err != nil {
+			err = fmt.Errorf("sioc: cannot create a new instance of %v: %w", sw.serviceType, err)
+		}
+		return empty, err
+	}
+	return copied.(T), nil
 }
 
 // Backward compatibility: getNewInstance is an alias for CreateNewService.
@@ -51,17 +80,16 @@ func (sw *serviceWrapper[T]) getNewInstance() T {
 	return sw.CreateNewService()
 }
 
-// MatchesServiceName checks if the given name matches the wrapper's service name.
+// MatchesServiceName checks if the given name is the canonical key of the service's type, as
+// built by typekey.Of. Short names such as "*pkg.Service" are ambiguous and do not match.
 func (sw *serviceWrapper[T]) MatchesServiceName(serviceName string) bool {
-	return sw.serviceName == sw.sanitizeServiceName(serviceName)
+	if sw.serviceType == nil {
+		return false
+	}
+	return serviceName == sw.serviceName
 }
 
 // Backward compatibility: matchWithName is an alias for MatchesServiceName.
 func (sw *serviceWrapper[T]) matchWithName(name string) bool {
 	return sw.MatchesServiceName(name)
 }
-
-// sanitizeServiceName sanitizes the service name for consistent storage.
-func (sw *serviceWrapper[T]) sanitizeServiceName(serviceName string) string {
-	return text.Sanitize(serviceName)
-}
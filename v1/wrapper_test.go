@@ -52,10 +52,11 @@ func TestServiceWrapperWithStruct(t *testing.T) {
 		t.Errorf("Expected {Name: test, ID: 42}, got %+v", retrieved)
 	}
 
-	// Test CreateNewService creates a copy
+	// Test CreateNewService creates a deep copy
 	newStruct := wrapper.CreateNewService()
-	// For pointer types, CreateNewService returns the same pointer value,
-	// not a deep copy. This is expected behavior.
+	if newStruct == testStruct {
+		t.Error("Expected a new pointer, not the stored one")
+	}
 	if newStruct.Name != "test" || newStruct.ID != 42 {
 		t.Errorf("Expected copy with {Name: test, ID: 42}, got %+v", newStruct)
 	}
@@ -106,25 +107,24 @@ func TestServiceWrapperBackwardCompatibilityMethodsDetailed(t *testing.T) {
 	}
 }
 
-// TestServiceWrapperNameSanitization tests that service names are properly sanitized
-func TestServiceWrapperNameSanitization(t *testing.T) {
-	wrapper := NewServiceWrapper[string]()
-	concreteWrapper := wrapper.(*serviceWrapper[string])
-
-	testService := "test"
-	wrapper.SetService(testService)
+// TestServiceWrapperCanonicalName tests that service names are canonical type keys
+func TestServiceWrapperCanonicalName(t *testing.T) {
+	wrapper := NewServiceWrapper[any]()
+	concreteWrapper := wrapper.(*serviceWrapper[any])
 
-	// Test with sanitized name
-	typeName := reflect.TypeOf(testService).String()
-	sanitized := concreteWrapper.sanitizeServiceName(typeName)
+	wrapper.SetService(&TestStruct{})
 
-	if !wrapper.MatchesServiceName(sanitized) {
-		t.Error("Should match sanitized name")
+	if concreteWrapper.serviceName != "*github.com/sergiodii/sioc/v1.TestStruct" {
+		t.Errorf("Expected the canonical key, got %v", concreteWrapper.serviceName)
 	}
-
-	// Test that internal name is sanitized
-	if concreteWrapper.serviceName != sanitized {
-		t.Errorf("Expected internal name to be %v, got %v", sanitized, concreteWrapper.serviceName)
+	if !wrapper.MatchesServiceName("*github.com/sergiodii/sioc/v1.TestStruct") {
+		t.Error("Should match the canonical key")
+	}
+	if wrapper.MatchesServiceName("*sioc.TestStruct") {
+		t.Error("Should not match the ambiguous reflect name")
+	}
+	if wrapper.MatchesServiceName("sioc.TestStruct") {
+		t.Error("Should not match a different pointer depth")
 	}
 }
 